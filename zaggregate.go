@@ -0,0 +1,185 @@
+package zorm
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const zAggregateAlias = "zorm_value"
+
+// Sum gives the sum of column of the current query,
+// the value is not valid if no row matches
+func (model *zModel) Sum(column string) (sql.NullFloat64, *zModelErr) {
+	return model.SumContext(context.Background(), column)
+}
+
+func (model *zModel) SumContext(ctx context.Context, column string) (sql.NullFloat64, *zModelErr) {
+	return model.aggregateFloat(ctx, "sum", column)
+}
+
+// Avg gives the average of column of the current query,
+// the value is not valid if no row matches
+func (model *zModel) Avg(column string) (sql.NullFloat64, *zModelErr) {
+	return model.AvgContext(context.Background(), column)
+}
+
+func (model *zModel) AvgContext(ctx context.Context, column string) (sql.NullFloat64, *zModelErr) {
+	return model.aggregateFloat(ctx, "avg", column)
+}
+
+// Min gives the minimum of column of the current query converted to
+// the type of the column in the table, nil if no row matches
+func (model *zModel) Min(column string) (interface{}, *zModelErr) {
+	return model.MinContext(context.Background(), column)
+}
+
+func (model *zModel) MinContext(ctx context.Context, column string) (interface{}, *zModelErr) {
+	return model.aggregate(ctx, "min", column, model.columnTemplate(column))
+}
+
+// Max gives the maximum of column of the current query converted to
+// the type of the column in the table, nil if no row matches
+func (model *zModel) Max(column string) (interface{}, *zModelErr) {
+	return model.MaxContext(context.Background(), column)
+}
+
+func (model *zModel) MaxContext(ctx context.Context, column string) (interface{}, *zModelErr) {
+	return model.aggregate(ctx, "max", column, model.columnTemplate(column))
+}
+
+// Value gives column of the first row of the current query,
+// nil if no row is found
+func (model *zModel) Value(column string) (interface{}, *zModelErr) {
+	return model.ValueContext(context.Background(), column)
+}
+
+func (model *zModel) ValueContext(ctx context.Context, column string) (interface{}, *zModelErr) {
+	var syntax = model.selectSyntax()
+	syntax.exprs = nil
+	syntax.limit = new(zLimit).Limit(1)
+
+	rows,err := model.selectRows(ctx, syntax, &ZColumnList{column + " AS " + zAggregateAlias: model.columnTemplate(column)})
+	if err != nil {
+		return nil, err
+	}
+
+	for _,row := range rows.Rows() {
+		v,_ := row.Get(zAggregateAlias)
+		return v, nil
+	}
+
+	return nil, nil
+}
+
+// Pluck gives column of all the rows of the current query
+func (model *zModel) Pluck(column string) ([]interface{}, *zModelErr) {
+	return model.PluckContext(context.Background(), column)
+}
+
+func (model *zModel) PluckContext(ctx context.Context, column string) ([]interface{}, *zModelErr) {
+	var syntax = model.selectSyntax()
+	syntax.exprs = nil
+
+	rows,err := model.selectRows(ctx, syntax, &ZColumnList{column + " AS " + zAggregateAlias: model.columnTemplate(column)})
+	if err != nil {
+		return nil, err
+	}
+
+	var values = make([]interface{}, 0, rows.Count())
+	for _,row := range rows.Rows() {
+		v,_ := row.Get(zAggregateAlias)
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+// Exists tells if any row matches the current query
+func (model *zModel) Exists() (bool, *zModelErr) {
+	return model.ExistsContext(context.Background())
+}
+
+func (model *zModel) ExistsContext(ctx context.Context) (bool, *zModelErr) {
+	var syntax = model.selectSyntax()
+	syntax.exprs = []zColumnExpr{Raw("1").As(zAggregateAlias)}
+	syntax.orderby = nil
+	syntax.limit = new(zLimit).Limit(1)
+
+	rows,err := model.selectRows(ctx, syntax, &ZColumnList{})
+	if err != nil {
+		return false, err
+	}
+
+	return rows.Count() > 0, nil
+}
+
+func (model *zModel) aggregateFloat(ctx context.Context, fn string, column string) (sql.NullFloat64, *zModelErr) {
+	v,err := model.aggregate(ctx, fn, column, float64(0))
+	if err != nil || v == nil {
+		return sql.NullFloat64{}, err
+	}
+
+	f,ok := v.(float64)
+	if !ok {
+		return sql.NullFloat64{}, &zModelErr{err:errors.Errorf("can not convert %s(%s) of %T to float", fn, column, v)}
+	}
+
+	return sql.NullFloat64{Float64: f, Valid: true}, nil
+}
+
+// aggregate gives the value of the function fn of column over the rows of
+// the current query converted to the type of template, nil for NULL.
+// A grouped query is refused as it gives a value of each group.
+func (model *zModel) aggregate(ctx context.Context, fn string, column string, template interface{}) (interface{}, *zModelErr) {
+	if err := model.checkStrict(column); err != nil {
+		return nil, &zModelErr{err:err}
+	}
+
+	var syntax = model.selectSyntax()
+	if syntax.groupby != nil && len(syntax.groupby.columns) > 0 {
+		return nil, &zModelErr{err:errors.New("aggregate of a grouped query is not supported, use Get with the expression")}
+	}
+	syntax.exprs = []zColumnExpr{{expr: fn + "(" + quoteColumn(model.dialect, column) + ")", alias: zAggregateAlias}}
+	syntax.orderby = nil
+	syntax.limit = nil
+	syntax.lock = nil
+
+	var row = ZColumnList{zAggregateAlias: template}.makeRow()
+	query,args,serr := syntax.query()
+	if serr != nil {
+		return nil, &zModelErr{query:query, args:args, err:serr}
+	}
+
+	sqlRow,err := model.queryRow(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if serr = row.fill(sqlRow); serr != nil {
+		return nil, &zModelErr{query:query, args:args, err:serr}
+	}
+
+	v,_ := row.Get(zAggregateAlias)
+	return v, nil
+}
+
+// columnTemplate gives the template of column in the table columns,
+// the table alias of column is ignored
+func (model *zModel) columnTemplate(column string) interface{} {
+	var columns = model.table.Columns()
+	if columns == nil {
+		return nil
+	}
+
+	if v,ok := (*columns)[column]; ok {
+		return v
+	}
+	if idxDot := strings.LastIndex(column, "."); idxDot > 0 {
+		return (*columns)[column[idxDot+1:]]
+	}
+
+	return nil
+}
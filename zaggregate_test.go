@@ -0,0 +1,71 @@
+package zorm
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"testing"
+)
+
+func TestZModel_Aggregate(t *testing.T) {
+	var connection = zTestConnection(t, "model_aggregate")
+	var model = connection.NewModel(new(zTestTable2), nil)
+	model.NewQuery().Where("c2", ">", 1).OrderBy("id", "DESC")
+
+	zTestResults["model_aggregate"] = [][]driver.Value{{[]byte("10.5")}}
+	sum, err := model.Sum("c4")
+	if err != nil || !sum.Valid || sum.Float64 != 10.5 {
+		t.Error("unexpected sum", sum, err)
+	}
+
+	zTestResults["model_aggregate"] = [][]driver.Value{{nil}}
+	avg, _ := model.Avg("c4")
+	max, _ := model.Max("c2")
+	if avg.Valid || max != nil {
+		t.Error("empty set should give null")
+	}
+
+	zTestResults["model_aggregate"] = [][]driver.Value{{int64(3)}}
+	if min, _ := model.Min("c2"); min != int16(3) {
+		t.Error("min should take the column type", min)
+	}
+
+	zTestResults["model_aggregate"] = [][]driver.Value{{[]byte("a")}, {[]byte("b")}}
+	values, _ := model.Pluck("c1")
+	value, _ := model.Value("c1")
+	if len(values) != 2 || values[1] != "b" || value != "a" {
+		t.Error("unexpected pluck values", values, value)
+	}
+
+	exists, _ := model.Exists()
+	zTestResults["model_aggregate"] = nil
+	if notExists, _ := model.Exists(); !exists || notExists {
+		t.Error("unexpected exists result")
+	}
+
+	queries := zTestQueries("model_aggregate")
+	fmt.Println(queries)
+	if queries[0] != "SELECT sum(`c4`) AS `zorm_value` FROM `test2` WHERE (`delete_time` = ?) AND ((`c2` > ?))" {
+		t.Error("unexpected aggregate query: " + queries[0])
+	}
+	if queries[6] != "SELECT 1 AS `zorm_value` FROM `test2` WHERE (`delete_time` = ?) AND ((`c2` > ?)) LIMIT ? OFFSET ?" {
+		t.Error("unexpected exists query: " + queries[6])
+	}
+}
+
+func TestZModel_AggregateErrors(t *testing.T) {
+	var connection = zTestConnection(t, "model_aggregate_errors")
+	var model = connection.NewModel(new(zTestTable1), nil)
+
+	zTestResults["model_aggregate_errors"] = [][]driver.Value{{[]byte("not a number")}}
+	if _, err := model.Sum("c4"); err == nil {
+		t.Error("a value which is not a number should fail")
+	}
+
+	model.NewQuery().GroupBy("c1")
+	if _, err := model.Max("c2"); err == nil {
+		t.Error("aggregate of a grouped query should be refused")
+	}
+	if len(zTestQueries("model_aggregate_errors")) != 1 {
+		t.Error("the grouped aggregate should not be run")
+	}
+}
@@ -0,0 +1,105 @@
+package zorm
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// Chunk calls fn with the rows of the current query in chunks of size rows,
+// paging by the primary key (WHERE pk > last ORDER BY pk LIMIT size)
+// instead of OFFSET, the columns of a composite key are compared as a row
+// value. The chunks are ordered by the key, so the query should have no
+// OrderBy or Limit. It stops at the first error returned by fn.
+func (model *zModel) Chunk(size int64, column *ZColumnList, fn func(rows *zRows) error) (*zModelErr) {
+	return model.ChunkContext(context.Background(), size, column, fn)
+}
+
+func (model *zModel) ChunkContext(ctx context.Context, size int64, column *ZColumnList, fn func(rows *zRows) error) (*zModelErr) {
+	return model.chunk(ctx, size, primaryKeys(model.table), column, fn)
+}
+
+// ChunkByID is like Chunk but pages by idColumn, which should be
+// unique and sortable, e.g. "t1.id" of a join table
+func (model *zModel) ChunkByID(size int64, idColumn string, column *ZColumnList, fn func(rows *zRows) error) (*zModelErr) {
+	return model.ChunkByIDContext(context.Background(), size, idColumn, column, fn)
+}
+
+func (model *zModel) ChunkByIDContext(ctx context.Context, size int64, idColumn string, column *ZColumnList, fn func(rows *zRows) error) (*zModelErr) {
+	if idColumn == "" {
+		return &zModelErr{err:errors.New("no id column to chunk by")}
+	}
+
+	return model.chunk(ctx, size, []string{idColumn}, column, fn)
+}
+
+func (model *zModel) chunk(ctx context.Context, size int64, keys []string, column *ZColumnList, fn func(rows *zRows) error) (*zModelErr) {
+	if size <= 0 {
+		return &zModelErr{err:errors.New("chunk size should be positive")}
+	}
+
+	if len(keys) == 0 {
+		return &zModelErr{err:errors.New("no key column to chunk by")}
+	}
+
+	if model.query != nil && (model.query.orderBy != nil || model.query.limit != nil) {
+		return &zModelErr{err:errors.New("chunks are ordered by the key, the query should have no order by or limit")}
+	}
+
+	if column == nil {
+		column = model.table.Columns()
+	}
+	var columns = ZColumnList{}
+	for k, v := range *column {
+		columns[k] = v
+	}
+	for _, key := range keys {
+		if _, ok := columns[key]; !ok {
+			columns[key] = nil
+		}
+	}
+
+	var last []interface{}
+	for {
+		var syntax = model.selectSyntax()
+		switch {
+		case last == nil:
+		case len(keys) == 1:
+			syntax.where = new(zWhere).Where(keys[0], ">", last[0]).AndWhere(syntax.where)
+		default:
+			syntax.where = new(zWhere).TupleWhere(keys, ">", last).AndWhere(syntax.where)
+		}
+		syntax.orderby = new(zOrderBy)
+		for _, key := range keys {
+			syntax.orderby.OrderBy(key, "ASC")
+		}
+		syntax.limit = new(zLimit).Limit(size)
+
+		rows, err := model.selectRows(ctx, syntax, &columns)
+		if err != nil {
+			return err
+		}
+
+		if rows.Count() == 0 {
+			return nil
+		}
+
+		if ferr := fn(rows); ferr != nil {
+			return &zModelErr{err:ferr}
+		}
+
+		if rows.Count() < size {
+			return nil
+		}
+
+		lastRow := rows.rows[len(rows.rows)-1]
+		last = make([]interface{}, 0, len(keys))
+		for _, key := range keys {
+			v, _ := lastRow.Get(key)
+			if v == nil {
+				return &zModelErr{err:errors.New("no value of key column " + key)}
+			}
+			last = append(last, v)
+		}
+	}
+}
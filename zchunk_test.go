@@ -0,0 +1,65 @@
+package zorm
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestZModel_Chunk(t *testing.T) {
+	var connection = zTestConnection(t, "model_chunk")
+	zTestResults["model_chunk"] = [][]driver.Value{{int64(1)}, {int64(2)}}
+
+	var model = connection.NewModel(new(zTestTable2), nil)
+	model.NewQuery().Where("c1", "=", "a")
+
+	var chunks = 0
+	err := model.Chunk(2, &ZColumnList{"id": int64(0)}, func(rows *zRows) error {
+		chunks++
+		if chunks == 2 {
+			zTestResults["model_chunk"] = [][]driver.Value{{int64(3)}}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	queries := zTestQueries("model_chunk")
+	fmt.Println(queries)
+	if chunks != 3 || len(queries) != 3 {
+		t.Error("unexpected chunks")
+	}
+	if queries[1] != "SELECT `id` FROM `test2` WHERE (`id` > ?) AND ((`delete_time` = ?) AND ((`c1` = ?))) ORDER BY `id` ASC LIMIT ? OFFSET ?" {
+		t.Error("unexpected chunk query: " + queries[1])
+	}
+}
+
+func TestZModel_ChunkComposite(t *testing.T) {
+	var connection = zTestConnection(t, "model_chunk_composite")
+	zTestResults["model_chunk_composite"] = [][]driver.Value{{int64(1), []byte("a")}}
+
+	var model = connection.NewModel(new(zTestTable3), nil)
+	var chunks = 0
+	err := model.Chunk(1, &ZColumnList{"tenant_id": int64(0), "id": ""}, func(rows *zRows) error {
+		chunks++
+		if chunks == 2 {
+			zTestResults["model_chunk_composite"] = nil
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	queries := zTestQueries("model_chunk_composite")
+	if len(queries) != 3 || !strings.HasSuffix(queries[1], " FROM `test3` WHERE ((`tenant_id`,`id`) > (?,?)) ORDER BY `tenant_id` ASC,`id` ASC LIMIT ? OFFSET ?") {
+		t.Error("unexpected composite chunk queries", queries)
+	}
+
+	model.NewQuery().OrderBy("c1", "DESC")
+	if err := model.Chunk(1, nil, func(rows *zRows) error { return nil }); err == nil {
+		t.Error("the order of the query should be refused")
+	}
+}
@@ -1,6 +1,9 @@
 package zorm
 
-import "strings"
+import (
+	"strings"
+	"github.com/pkg/errors"
+)
 
 type zWhereCond struct {
 	logical 	string
@@ -9,35 +12,71 @@ type zWhereCond struct {
 	value 		interface{}
 }
 
-func (cond *zWhereCond) build() (query string, args []interface{}) {
+// build renders the condition, the columns are quoted by the dialect
+func (cond *zWhereCond) build(dialect Dialect) (query string, args []interface{}) {
+	var column = quoteColumn(dialect, cond.column)
 	switch cond.operation {
 	case "RAW":
 		query = cond.column
 		args = cond.value.([]interface{})
 	case "WHERE":
-		return cond.value.(*zWhere).build()
+		return cond.value.(*zWhere).build(dialect)
 	case "IN":
+		// an empty list matches nothing
 		args = cond.value.([]interface{})
-		if args==nil || len(args) == 0 {
-			query = ""
+		if len(args) == 0 {
+			query = "1=0"
 		} else {
-			query = cond.column + " IN (" + strings.Trim(strings.Repeat("?,", len(args)), ",") + ")"
+			query = column + " IN (" + strings.Trim(strings.Repeat("?,", len(args)), ",") + ")"
 		}
-	case "LIKE":
-		args = nil
-		pattern := cond.value.(string)
-		if strings.HasPrefix(pattern, "'") && strings.HasSuffix(pattern, "'") {
-			query = cond.column + " LIKE " + cond.value.(string)
+	case "NOT IN":
+		// an empty list excludes nothing
+		args = cond.value.([]interface{})
+		if len(args) == 0 {
+			query = "1=1"
 		} else {
-
-			query = cond.column + " LIKE '" + strings.Trim(pattern, "'") + "'"
+			query = column + " NOT IN (" + strings.Trim(strings.Repeat("?,", len(args)), ",") + ")"
 		}
+	case "LIKE":
+		query,args = cond.value.(zLike).build(column)
 	case "RANGE":
-		query = cond.column + " BETWEEN ? AND ?"
+		query = column + " BETWEEN ? AND ?"
 		args = cond.value.([]interface{})
+	case "NOT RANGE":
+		query = column + " NOT BETWEEN ? AND ?"
+		args = cond.value.([]interface{})
+	case "COLUMN":
+		compare := cond.value.(zColumnCompare)
+		query = column + " " + compare.operation + " " + quoteColumn(dialect, compare.column)
+		args = nil
+	case "TUPLE":
+		query,args = cond.value.(zTuple).build(dialect)
+	case "NOT":
+		wquery,wargs := cond.value.(*zWhere).build(dialect)
+		query = "NOT (" + wquery + ")"
+		args = wargs
+	case "IN SUB":
+		sub := cond.value.(*zSubQuery)
+		query = column + " IN (" + sub.query + ")"
+		args = sub.args
+	case "EXISTS", "NOT EXISTS":
+		sub := cond.value.(*zSubQuery)
+		query = cond.operation + " (" + sub.query + ")"
+		args = sub.args
+	case "NULL":
+		query = column + " IS NULL"
+		args = nil
+	case "NOT NULL":
+		query = column + " IS NOT NULL"
+		args = nil
 	default:
-		query = cond.column + " " + cond.operation + " ?"
-		args = []interface{}{cond.value}
+		if sub, ok := cond.value.(*zSubQuery); ok {
+			query = column + " " + cond.operation + " (" + sub.query + ")"
+			args = sub.args
+		} else {
+			query = column + " " + cond.operation + " ?"
+			args = []interface{}{cond.value}
+		}
 	}
 
 	return
@@ -46,6 +85,13 @@ func (cond *zWhereCond) build() (query string, args []interface{}) {
 // Where clause
 type zWhere struct {
 	cond 		[]zWhereCond
+	// the first invalid condition, returned by the syntax query
+	err 		error
+}
+
+// zCompareOperations are the comparisons allowed by TupleWhere and CompareColumns
+var zCompareOperations = map[string]bool{
+	"=": true, "<>": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true,
 }
 
 func (where *zWhere) Raw(query string, args ...interface{}) (*zWhere) {
@@ -66,7 +112,50 @@ func (where *zWhere) Raw(query string, args ...interface{}) (*zWhere) {
 	return where
 }
 
+// Like matches column with the pattern bound as an arg
 func (where *zWhere) Like(column,pattern string) (*zWhere) {
+	return where.like(column, zLike{pattern: pattern})
+}
+
+func (where *zWhere) NotLike(column,pattern string) (*zWhere) {
+	return where.like(column, zLike{pattern: pattern, not: true})
+}
+
+// ILike matches column with the pattern case-insensitively
+func (where *zWhere) ILike(column,pattern string) (*zWhere) {
+	return where.like(column, zLike{pattern: pattern, fold: true})
+}
+
+func (where *zWhere) NotILike(column,pattern string) (*zWhere) {
+	return where.like(column, zLike{pattern: pattern, not: true, fold: true})
+}
+
+// Contains matches column containing value, the wildcards in value are escaped
+func (where *zWhere) Contains(column,value string) (*zWhere) {
+	return where.like(column, zLike{pattern: "%" + escapeLike(value) + "%", escape: true})
+}
+
+func (where *zWhere) StartsWith(column,value string) (*zWhere) {
+	return where.like(column, zLike{pattern: escapeLike(value) + "%", escape: true})
+}
+
+func (where *zWhere) EndsWith(column,value string) (*zWhere) {
+	return where.like(column, zLike{pattern: "%" + escapeLike(value), escape: true})
+}
+
+func (where *zWhere) IContains(column,value string) (*zWhere) {
+	return where.like(column, zLike{pattern: "%" + escapeLike(value) + "%", fold: true, escape: true})
+}
+
+func (where *zWhere) IStartsWith(column,value string) (*zWhere) {
+	return where.like(column, zLike{pattern: escapeLike(value) + "%", fold: true, escape: true})
+}
+
+func (where *zWhere) IEndsWith(column,value string) (*zWhere) {
+	return where.like(column, zLike{pattern: "%" + escapeLike(value), fold: true, escape: true})
+}
+
+func (where *zWhere) like(column string, like zLike) (*zWhere) {
 	if column == "" {
 		return where
 	}
@@ -79,13 +168,23 @@ func (where *zWhere) Like(column,pattern string) (*zWhere) {
 		logical: "AND",
 		column: column,
 		operation: "LIKE",
-		value: pattern,
+		value: like,
 	})
 	return where
 }
 
+// In matches column in the values, an empty list gives 1=0
 func (where *zWhere) In(column string, value ...interface{}) (*zWhere) {
-	if column=="" || value==nil || len(value)==0 {
+	return where.in(column, "IN", value)
+}
+
+// NotIn matches column not in the values, an empty list gives 1=1
+func (where *zWhere) NotIn(column string, value ...interface{}) (*zWhere) {
+	return where.in(column, "NOT IN", value)
+}
+
+func (where *zWhere) in(column, operation string, value []interface{}) (*zWhere) {
+	if column=="" {
 		return where
 	}
 
@@ -96,16 +195,19 @@ func (where *zWhere) In(column string, value ...interface{}) (*zWhere) {
 	where.cond = append(where.cond, zWhereCond{
 		logical: "AND",
 		column: column,
-		operation: "IN",
+		operation: operation,
 		value: value,
 	})
 	return where
 }
 
 func (where *zWhere) Between(column string, v1, v2 interface{}) (*zWhere) {
-	if column=="" || v1==nil || v2==nil {
+	if column == "" {
 		return where
 	}
+	if v1 == nil || v2 == nil {
+		return where.fail(errors.Errorf("nil bound of between %q", column))
+	}
 
 	if where.cond == nil {
 		where.cond = make([]zWhereCond, 0)
@@ -120,11 +222,132 @@ func (where *zWhere) Between(column string, v1, v2 interface{}) (*zWhere) {
 	return where
 }
 
+func (where *zWhere) NotBetween(column string, v1, v2 interface{}) (*zWhere) {
+	if column == "" {
+		return where
+	}
+	if v1 == nil || v2 == nil {
+		return where.fail(errors.Errorf("nil bound of between %q", column))
+	}
+
+	if where.cond == nil {
+		where.cond = make([]zWhereCond, 0)
+	}
+
+	where.cond = append(where.cond, zWhereCond{
+		logical: "AND",
+		column: column,
+		operation: "NOT RANGE",
+		value: []interface{}{v1, v2},
+	})
+	return where
+}
+
+// InSub renders column IN (SELECT ...)
+func (where *zWhere) InSub(column string, sub *zSubQuery) (*zWhere) {
+	if column == "" || sub == nil {
+		return where
+	}
+
+	if where.cond == nil {
+		where.cond = make([]zWhereCond, 0)
+	}
+
+	where.cond = append(where.cond, zWhereCond{
+		logical: "AND",
+		column: column,
+		operation: "IN SUB",
+		value: sub,
+	})
+	return where
+}
+
+// Exists renders EXISTS (SELECT ...)
+func (where *zWhere) Exists(sub *zSubQuery) (*zWhere) {
+	if sub == nil {
+		return where
+	}
+
+	if where.cond == nil {
+		where.cond = make([]zWhereCond, 0)
+	}
+
+	where.cond = append(where.cond, zWhereCond{
+		logical: "AND",
+		operation: "EXISTS",
+		value: sub,
+	})
+	return where
+}
+
+// NotExists renders NOT EXISTS (SELECT ...)
+func (where *zWhere) NotExists(sub *zSubQuery) (*zWhere) {
+	if sub == nil {
+		return where
+	}
+
+	if where.cond == nil {
+		where.cond = make([]zWhereCond, 0)
+	}
+
+	where.cond = append(where.cond, zWhereCond{
+		logical: "AND",
+		operation: "NOT EXISTS",
+		value: sub,
+	})
+	return where
+}
+
+func (where *zWhere) Null(column string) (*zWhere) {
+	if column == "" {
+		return where
+	}
+
+	if where.cond == nil {
+		where.cond = make([]zWhereCond, 0)
+	}
+
+	where.cond = append(where.cond, zWhereCond{
+		logical: "AND",
+		column: column,
+		operation: "NULL",
+	})
+	return where
+}
+
+func (where *zWhere) NotNull(column string) (*zWhere) {
+	if column == "" {
+		return where
+	}
+
+	if where.cond == nil {
+		where.cond = make([]zWhereCond, 0)
+	}
+
+	where.cond = append(where.cond, zWhereCond{
+		logical: "AND",
+		column: column,
+		operation: "NOT NULL",
+	})
+	return where
+}
+
+// Where compares column with the value, a nil value
+// gives IS NULL for = and IS NOT NULL for != and <>
 func (where *zWhere) Where(column,operation string, value interface{}) (*zWhere) {
-	if column == "" || value==nil {
+	if column == "" {
 		return where
 	}
 
+	if value == nil {
+		switch operation {
+		case "=":
+			return where.Null(column)
+		case "!=", "<>":
+			return where.NotNull(column)
+		}
+	}
+
 	if where.cond == nil {
 		where.cond = make([]zWhereCond, 0)
 	}
@@ -138,6 +361,99 @@ func (where *zWhere) Where(column,operation string, value interface{}) (*zWhere)
 	return where
 }
 
+// CompareColumns compares the column with another column
+//
+//	CompareColumns("t1.update_time", ">", "t1.create_time")
+func (where *zWhere) CompareColumns(column,operation,other string) (*zWhere) {
+	if column == "" || other == "" {
+		return where
+	}
+
+	if !zCompareOperations[operation] {
+		return where.fail(errors.Errorf("invalid column comparison %q", operation))
+	}
+
+	if where.cond == nil {
+		where.cond = make([]zWhereCond, 0)
+	}
+
+	where.cond = append(where.cond, zWhereCond{
+		logical: "AND",
+		column: column,
+		operation: "COLUMN",
+		value: zColumnCompare{operation: operation, column: other},
+	})
+	return where
+}
+
+// TupleIn matches the columns in the rows of values, each row has
+// a value for every column, no row gives 1=0
+//
+//	TupleIn([]string{"tenant_id", "id"}, []interface{}{1, 10}, []interface{}{1, 11})
+//	(tenant_id,id) IN ((?,?),(?,?))
+func (where *zWhere) TupleIn(columns []string, values ...[]interface{}) (*zWhere) {
+	return where.tuple(zTuple{columns: columns, operation: "IN", values: values})
+}
+
+// TupleWhere compares the columns with the values as row values
+//
+//	TupleWhere([]string{"create_time", "id"}, ">", []interface{}{t, 10})
+//	(create_time,id) > (?,?)
+func (where *zWhere) TupleWhere(columns []string, operation string, values []interface{}) (*zWhere) {
+	return where.tuple(zTuple{columns: columns, operation: operation, values: [][]interface{}{values}})
+}
+
+func (where *zWhere) tuple(tuple zTuple) (*zWhere) {
+	if len(tuple.columns) == 0 {
+		return where.fail(errors.New("tuple has no columns"))
+	}
+
+	if tuple.operation != "IN" && !zCompareOperations[tuple.operation] {
+		return where.fail(errors.Errorf("invalid tuple operation %q", tuple.operation))
+	}
+	for _,values := range tuple.values {
+		if len(values) != len(tuple.columns) {
+			return where.fail(errors.Errorf("tuple has %d values for %d columns", len(values), len(tuple.columns)))
+		}
+	}
+
+	if where.cond == nil {
+		where.cond = make([]zWhereCond, 0)
+	}
+
+	where.cond = append(where.cond, zWhereCond{
+		logical: "AND",
+		column: strings.Join(tuple.columns, ","),
+		operation: "TUPLE",
+		value: tuple,
+	})
+	return where
+}
+
+// Not negates the conditions of cond
+func (where *zWhere) Not(cond *zWhere) (*zWhere) {
+	if cond == nil {
+		return where
+	}
+	if len(cond.cond) == 0 {
+		if cond.err != nil {
+			return where.fail(cond.err)
+		}
+		return where
+	}
+
+	if where.cond == nil {
+		where.cond = make([]zWhereCond, 0)
+	}
+
+	where.cond = append(where.cond, zWhereCond{
+		logical: "AND",
+		operation: "NOT",
+		value: cond,
+	})
+	return where
+}
+
 func (where *zWhere) AndWhere(cond *zWhere) (*zWhere) {
 	if cond == nil {
 		return where
@@ -172,11 +488,37 @@ func (where *zWhere) OrWhere(cond *zWhere) (*zWhere) {
 	return where
 }
 
-func (where *zWhere) build() (query string, args []interface{}) {
+// fail records err if no error is recorded yet
+func (where *zWhere) fail(err error) (*zWhere) {
+	if where.err == nil {
+		where.err = err
+	}
+	return where
+}
+
+// error gives the first invalid condition of where and its groups
+func (where *zWhere) error() error {
+	if where.err != nil {
+		return where.err
+	}
+
+	for _,cond := range where.cond {
+		switch cond.operation {
+		case "WHERE", "NOT":
+			if err := cond.value.(*zWhere).error(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (where *zWhere) build(dialect Dialect) (query string, args []interface{}) {
 	query = ""
 	args = make([]interface{}, 0)
 	for _,cond := range where.cond {
-		cquery, cargs := cond.build()
+		cquery, cargs := cond.build(dialect)
 
 		if query == "" {
 			query = "(" + cquery + ")"
@@ -195,33 +537,79 @@ func (where *zWhere) build() (query string, args []interface{}) {
 // order by clause
 type zOrderBy struct {
 	columns 	[]string
+	// the first invalid column or direction, returned by the syntax query
+	err 		error
 }
 
+// OrderBy sorts by column, ASC if sort is empty, an invalid column
+// or direction is returned as an error by the query
 func (orderby *zOrderBy) OrderBy(column,sort string) (*zOrderBy) {
 	if column == "" {
 		return orderby
 	}
 
+	if !ValidIdentifier(column) {
+		return orderby.fail(errors.Errorf("invalid order by column %q", column))
+	}
+
 	if orderby.columns == nil {
 		orderby.columns = make([]string, 0)
 	}
 
-	switch strings.ToUpper(sort) {
+	switch strings.ToUpper(strings.TrimSpace(sort)) {
 	case "":
 		fallthrough
 	case "ASC":
 		orderby.columns = append(orderby.columns, column + " ASC")
 	case "DESC":
 		orderby.columns = append(orderby.columns, column + " DESC")
+	default:
+		return orderby.fail(errors.Errorf("invalid order by direction %q", sort))
 	}
 	return orderby
 }
 
-func (orderby *zOrderBy) build() (query string, args []interface{}) {
+func (orderby *zOrderBy) fail(err error) (*zOrderBy) {
+	if orderby.err == nil {
+		orderby.err = err
+	}
+	return orderby
+}
+
+func (orderby *zOrderBy) error() error {
+	return orderby.err
+}
+
+// zOrderItem is a column of the order by clause
+type zOrderItem struct {
+	column 		string
+	desc 		bool
+}
+
+// items gives the columns and directions of the order by clause
+func (orderby *zOrderBy) items() ([]zOrderItem) {
+	var items = make([]zOrderItem, 0, len(orderby.columns))
+	for _,column := range orderby.columns {
+		idx := strings.LastIndex(column, " ")
+		items = append(items, zOrderItem{column: column[:idx], desc: column[idx+1:] == "DESC"})
+	}
+	return items
+}
+
+func (orderby *zOrderBy) build(dialect Dialect) (query string, args []interface{}) {
 	if orderby.columns == nil {
 		return "",nil
 	}
-	query = strings.Join(orderby.columns, ",")
+
+	var columns = make([]string, 0, len(orderby.columns))
+	for _,item := range orderby.items() {
+		if item.desc {
+			columns = append(columns, quoteColumn(dialect, item.column) + " DESC")
+		} else {
+			columns = append(columns, quoteColumn(dialect, item.column) + " ASC")
+		}
+	}
+	query = strings.Join(columns, ",")
 	return query,nil
 }
 
@@ -241,33 +629,30 @@ func (limit *zLimit) Offset(offset int64) (*zLimit) {
 	return limit
 }
 
-// LIMIT row_count OFFSET Offset
-func (limit *zLimit) build() (query string, args []interface{}) {
-	query = "? OFFSET ?"
-	args = []interface{}{limit.rowCount, limit.iOffset}
-
-	return query, args
-}
-
-// LIMIT row_count
-func (limit *zLimit) build2() (query string, args []interface{}) {
-	query = "?"
-	args = []interface{}{limit.rowCount}
-
-	return query, args
-}
-
 // group by clause
 type zGroupBy struct {
 	columns 		[]string
 	havingCond 		*zWhere
+	// the first invalid column, returned by the syntax query
+	err 			error
 }
 
+// GroupBy groups by the columns, an invalid column is returned
+// as an error by the query
 func (groupby *zGroupBy) GroupBy(column ...string) (*zGroupBy) {
 	if column==nil || len(column)==0 {
 		return groupby
 	}
 
+	for _,c := range column {
+		if !ValidIdentifier(c) && groupby.err == nil {
+			groupby.err = errors.Errorf("invalid group by column %q", c)
+		}
+	}
+	if groupby.err != nil {
+		return groupby
+	}
+
 	if groupby.columns == nil {
 		groupby.columns = make([]string, 0)
 	}
@@ -276,20 +661,33 @@ func (groupby *zGroupBy) GroupBy(column ...string) (*zGroupBy) {
 	return groupby
 }
 
+// error gives the invalid column or the invalid having condition
+func (groupby *zGroupBy) error() error {
+	if groupby.err != nil {
+		return groupby.err
+	}
+
+	if groupby.havingCond != nil {
+		return groupby.havingCond.error()
+	}
+
+	return nil
+}
+
 func (groupby *zGroupBy) Having(where *zWhere) (*zGroupBy) {
 	groupby.havingCond = where
 	return groupby
 }
 
-func (groupby *zGroupBy) build() (query string, args []interface{}) {
+func (groupby *zGroupBy) build(dialect Dialect) (query string, args []interface{}) {
 	if groupby.columns == nil || len(groupby.columns) == 0 {
 		return "",nil
 	}
 
-	query = strings.Join(groupby.columns, ",")
+	query = strings.Join(quoteColumns(dialect, groupby.columns), ",")
 
 	if groupby.havingCond != nil {
-		cquery,cargs := groupby.havingCond.build()
+		cquery,cargs := groupby.havingCond.build(dialect)
 		query = query + " HAVING " + cquery
 		args = cargs
 	} else {
@@ -302,7 +700,7 @@ func (groupby *zGroupBy) build() (query string, args []interface{}) {
 // join clause
 type zJoinCondition interface {
 	// joinCondition returns the join condition sql query
-	joinCondition() (query string, args []interface{})
+	joinCondition(dialect Dialect) (query string, args []interface{})
 }
 
 //
@@ -310,9 +708,9 @@ type zJoinOn struct {
 	where 		*zWhere
 }
 
-func (join *zJoinOn) joinCondition() (query string, args []interface{}) {
+func (join *zJoinOn) joinCondition(dialect Dialect) (query string, args []interface{}) {
 	if join.where != nil {
-		query,args = join.where.build()
+		query,args = join.where.build(dialect)
 		if query != "" {
 			query = " ON " + query
 		}
@@ -328,10 +726,102 @@ type zJoinUsing struct {
 	columns 	[]string
 }
 
-func (join *zJoinUsing) joinCondition() (query string, args []interface{}) {
+func (join *zJoinUsing) joinCondition(dialect Dialect) (query string, args []interface{}) {
 	if join.columns != nil && len(join.columns)>0 {
-		return " USING (" + strings.Join(join.columns, ",") + ")", nil
+		return " USING (" + strings.Join(quoteColumns(dialect, join.columns), ",") + ")", nil
 	}
 
 	return "",nil
-}
\ No newline at end of file
+}
+// zLock is the row locking clause of a select
+type zLock struct {
+	mode 		string
+	option 		string
+}
+
+func (lock *zLock) build() (string, error) {
+	if lock.mode == "" {
+		if lock.option != "" {
+			return "", errors.New(lock.option + " needs LockForUpdate or LockForShare")
+		}
+		return "", nil
+	}
+
+	if lock.option == "" {
+		return "FOR " + lock.mode, nil
+	}
+
+	return "FOR " + lock.mode + " " + lock.option, nil
+}
+
+// zLikeEscape escapes the wildcards in the patterns of
+// Contains, StartsWith and EndsWith, it is the same in all dialects
+const zLikeEscape = "!"
+
+// zLike is the pattern of a LIKE condition
+type zLike struct {
+	pattern 	string
+	not 		bool
+	// case-insensitive
+	fold 		bool
+	// the pattern is escaped by zLikeEscape
+	escape 		bool
+}
+
+func (like zLike) build(column string) (query string, args []interface{}) {
+	var operation = " LIKE "
+	if like.not {
+		operation = " NOT LIKE "
+	}
+
+	if like.fold {
+		query = "LOWER(" + column + ")" + operation + "LOWER(?)"
+	} else {
+		query = column + operation + "?"
+	}
+
+	if like.escape {
+		query = query + " ESCAPE '" + zLikeEscape + "'"
+	}
+
+	return query, []interface{}{like.pattern}
+}
+
+// escapeLike escapes the wildcards % and _ in value,
+// used with Contains, StartsWith and EndsWith
+func escapeLike(value string) string {
+	return strings.NewReplacer(zLikeEscape, zLikeEscape + zLikeEscape, "%", zLikeEscape + "%", "_", zLikeEscape + "_").Replace(value)
+}
+
+// zColumnCompare is the right side of a column to column condition
+type zColumnCompare struct {
+	operation 	string
+	column 		string
+}
+
+// zTuple is a row value condition of several columns
+type zTuple struct {
+	columns 	[]string
+	operation 	string
+	values 		[][]interface{}
+}
+
+func (tuple zTuple) build(dialect Dialect) (query string, args []interface{}) {
+	var row = "(" + strings.Trim(strings.Repeat("?,", len(tuple.columns)), ",") + ")"
+	var columns = "(" + strings.Join(quoteColumns(dialect, tuple.columns), ",") + ")"
+
+	args = make([]interface{}, 0, len(tuple.values)*len(tuple.columns))
+	for _,values := range tuple.values {
+		args = append(args, values...)
+	}
+
+	if tuple.operation != "IN" {
+		return columns + " " + tuple.operation + " " + row, args
+	}
+
+	if len(tuple.values) == 0 {
+		return "1=0", nil
+	}
+
+	return columns + " IN (" + strings.Trim(strings.Repeat(row + ",", len(tuple.values)), ",") + ")", args
+}
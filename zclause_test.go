@@ -9,7 +9,7 @@ func TestZWhere_Where(t *testing.T) {
 	var where = new(zWhere)
 	where.Where("c1", "=", "test").Where("c2", ">", 10)
 
-	query, args := where.build()
+	query, args := where.build(nil)
 	fmt.Println(query, args)
 }
 
@@ -17,7 +17,7 @@ func TestZWhere_Like(t *testing.T) {
 	var where = new(zWhere)
 	where.Like("c1", "%pattern%").Like("c2", "'pattern%'")
 
-	query,args := where.build()
+	query,args := where.build(nil)
 	fmt.Println(query, args)
 }
 
@@ -26,7 +26,7 @@ func TestZWhere_In(t *testing.T) {
 
 	where.In("c1", 1,2,3,4).In("c2", []interface{}{"h", "b", "c"}...)
 
-	query,args := where.build()
+	query,args := where.build(nil)
 	fmt.Println(query, args)
 }
 
@@ -35,7 +35,7 @@ func TestZWhere_Raw(t *testing.T) {
 
 	where.Raw("c1 = ? OR c2 = ?", 12, 29).Raw("c3 = ? OR c4 = ?", 12, 19)
 
-	query, args := where.build()
+	query, args := where.build(nil)
 
 	fmt.Println(query, args)
 }
@@ -45,8 +45,12 @@ func TestZWhere_Between(t *testing.T) {
 
 	where.Between("c1", 12, 29).Between("c2", "2006-12-19", "2019-01-29")
 
-	query,args := where.build()
+	query,args := where.build(nil)
 	fmt.Println(query, args)
+
+	if new(zWhere).Between("c1", nil, 29).error() == nil || new(zWhere).NotBetween("c1", 12, nil).error() == nil {
+		t.Error("a nil bound should be an error")
+	}
 }
 
 func TestZWhere_AndWhere(t *testing.T) {
@@ -54,7 +58,7 @@ func TestZWhere_AndWhere(t *testing.T) {
 
 	where.Where("c1", "=", 12).AndWhere(new(zWhere).Raw("c1=? OR c2=?", 19, 28))
 
-	query,args := where.build()
+	query,args := where.build(nil)
 	fmt.Println(query, args)
 }
 
@@ -63,7 +67,7 @@ func TestZWhere_OrWhere(t *testing.T) {
 
 	where.Raw("c1=? AND c2=?", 12, 29).OrWhere(new(zWhere).Like("c3", "%ddd%").Where("c4", ">", 19))
 
-	query,args := where.build()
+	query,args := where.build(nil)
 	fmt.Println(query, args)
 }
 
@@ -72,7 +76,7 @@ func TestZOrderBy_OrderBy(t *testing.T) {
 
 	orderBy.OrderBy("c1", "").OrderBy("c2", "DESC").OrderBy("c3", "ASC")
 
-	query,args := orderBy.build()
+	query,args := orderBy.build(nil)
 
 	fmt.Println(query, args)
 }
@@ -82,14 +86,14 @@ func TestZGroupBy_GroupBy(t *testing.T) {
 
 	grouby.GroupBy("c1", "c2", "c3").GroupBy("c4", "c5", "c6")
 
-	fmt.Println(grouby.build())
+	fmt.Println(grouby.build(nil))
 }
 
 func TestZGroupBy_Having(t *testing.T) {
 	var groupby = new(zGroupBy)
 
 	groupby.GroupBy("c1", "c2").Having(WhereColumn("c1", ">", 100))
-	fmt.Println(groupby.build())
+	fmt.Println(groupby.build(nil))
 }
 
 func TestZLimit_Limit(t *testing.T) {
@@ -97,7 +101,11 @@ func TestZLimit_Limit(t *testing.T) {
 
 	limit.Limit(100).Offset(10)
 
-	fmt.Println(limit.build2())
+	var syntax = &zUpdate{table: new(zTestTable1), assigns: AssignList{"c1": 1}, limit: limit}
+	query,args,_ := syntax.query()
+	if query != "UPDATE `test1` SET `c1`=? LIMIT ?" || len(args) != 2 || args[1] != int64(100) {
+		t.Error("unexpected limit: ", query, args)
+	}
 }
 
 func TestZLimit_Offset(t *testing.T) {
@@ -105,5 +113,86 @@ func TestZLimit_Offset(t *testing.T) {
 
 	limit.Limit(100).Offset(10)
 
-	fmt.Println(limit.build())
-}
\ No newline at end of file
+	var syntax = &zSelect{table: new(zTestTable1), limit: limit}
+	query,args,_ := syntax.query("id")
+	if query != "SELECT `id` FROM `test1` LIMIT ? OFFSET ?" || len(args) != 2 || args[1] != int64(10) {
+		t.Error("unexpected limit with offset: ", query, args)
+	}
+}
+
+func TestZWhere_Null(t *testing.T) {
+	var where = new(zWhere)
+
+	where.Null("c1").NotNull("c2").Where("c3", "=", 1)
+
+	query,args := where.build(nil)
+	fmt.Println(query, args)
+}
+
+func TestZWhere_LikeBound(t *testing.T) {
+	var where = new(zWhere)
+	where.Like("c1", "'pattern%'").NotLike("c2", "a%").ILike("c3", "%B%").Contains("c4", "50%_off!")
+
+	query,args := where.build(nil)
+	fmt.Println(query, args)
+	if query != "(`c1` LIKE ?) AND (`c2` NOT LIKE ?) AND (LOWER(`c3`) LIKE LOWER(?)) AND (`c4` LIKE ? ESCAPE '!')" {
+		t.Error("unexpected like query: " + query)
+	}
+	if args[0] != "'pattern%'" || args[3] != "%50!%!_off!!%" {
+		t.Error("unexpected like args")
+	}
+
+	query,args = new(zWhere).IStartsWith("c1", "Ab").EndsWith("c2", "z").build(nil)
+	fmt.Println(query, args)
+	if query != "(LOWER(`c1`) LIKE LOWER(?) ESCAPE '!') AND (`c2` LIKE ? ESCAPE '!')" || args[0] != "Ab%" || args[1] != "%z" {
+		t.Error("unexpected like query: " + query)
+	}
+}
+
+func TestZWhere_Operators(t *testing.T) {
+	var where = new(zWhere)
+	where.In("c1").NotIn("c2").NotIn("c3", 1, 2).NotBetween("c4", 1, 9).
+		Where("c5", "=", nil).Where("c6", "<>", nil).
+		CompareColumns("c7", ">", "t.c8").Not(WhereIn("c9", 1).OrWhere(WhereNull("c10")))
+
+	query, args := where.build(nil)
+	fmt.Println(query, args)
+	if query != "(1=0) AND (1=1) AND (`c3` NOT IN (?,?)) AND (`c4` NOT BETWEEN ? AND ?) AND (`c5` IS NULL) AND (`c6` IS NOT NULL) " +
+		"AND (`c7` > `t`.`c8`) AND (NOT ((`c9` IN (?)) OR ((`c10` IS NULL))))" {
+		t.Error("unexpected where query: " + query)
+	}
+	if len(args) != 5 {
+		t.Error("unexpected where args")
+	}
+
+	if new(zWhere).CompareColumns("c1", "= 1 OR 1 =", "c2").error() == nil {
+		t.Error("the column comparison should be validated")
+	}
+}
+
+func TestZWhere_Tuple(t *testing.T) {
+	var where = new(zWhere)
+	where.TupleIn([]string{"a", "b"}, []interface{}{1, 2}, []interface{}{3, 4}).
+		TupleWhere([]string{"c", "d"}, ">", []interface{}{5, 6}).TupleIn([]string{"a", "b"})
+
+	query, args := where.build(nil)
+	fmt.Println(query, args)
+	if query != "((`a`,`b`) IN ((?,?),(?,?))) AND ((`c`,`d`) > (?,?)) AND (1=0)" || len(args) != 6 || args[5] != 6 {
+		t.Error("unexpected tuple query: " + query)
+	}
+
+	if new(zWhere).TupleIn([]string{"a", "b"}, []interface{}{1}).error() == nil {
+		t.Error("a row with missing values should be an error")
+	}
+	if new(zWhere).TupleIn(nil, []interface{}{1}).error() == nil {
+		t.Error("a tuple without columns should be an error")
+	}
+	if new(zWhere).TupleWhere([]string{"a", "b"}, "; DROP", []interface{}{1, 2}).error() == nil {
+		t.Error("the tuple operation should be validated")
+	}
+
+	var syntax = &zSelect{table: new(zTestTable1), where: new(zWhere).AndWhere(WhereTuple([]string{"a", "b"}, "LIKE", []interface{}{1, 2}))}
+	if _,_,err := syntax.query(); err == nil {
+		t.Error("the syntax should return the invalid tuple")
+	}
+}
@@ -1,8 +1,9 @@
 package zorm
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
+	"strconv"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/pkg/errors"
 )
@@ -10,14 +11,21 @@ import (
 type ZConnection struct {
 	connection *sql.DB
 	transaction *sql.Tx
+	savepoints []string
+	dialect Dialect
 }
 
 // OpenConnection defines a DB connection used in orm model
+// the dialect is chosen by ZConnectionCfg.Dialect, mysql by default
 func (connection *ZConnection) Open(con ZConnectionCfg) (*ZConnection, error)  {
+	dialect, ok := GetDialect(con.Dialect)
+	if !ok {
+		return nil, errors.New("unknown dialect " + con.Dialect)
+	}
+
 	var err error
-	connection.connection,err = sql.Open("mysql",
-		fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8&timeout=%ds&readTimeout=%ds&writeTimeout=%ds",
-			con.UserName, con.Password, con.Host, con.Port,con.DbName, con.TimeoutSec, con.ReadTimeoutSec, con.WriteTimeoutSec))
+	connection.dialect = dialect
+	connection.connection,err = sql.Open(dialect.DriverName(), dialect.DSN(con))
 	if err != nil {
 		return nil, err
 	}
@@ -28,6 +36,11 @@ func (connection *ZConnection) Open(con ZConnectionCfg) (*ZConnection, error)  {
 	return connection, nil
 }
 
+// Dialect returns the sql dialect of the connection
+func (connection *ZConnection) Dialect() Dialect {
+	return useDialect(connection.dialect)
+}
+
 // Close closes the database and prevents new queries from starting.
 func (connection *ZConnection) Close() (error) {
 	if connection.connection == nil {
@@ -38,43 +51,141 @@ func (connection *ZConnection) Close() (error) {
 }
 
 // Begin starts a transaction. The default isolation level is dependent on
-// the driver.
+// the driver. Models created by NewModel after Begin run in the transaction.
+// Begin inside an open transaction creates a savepoint, the following
+// Commit or Rollback releases or rolls back to the savepoint.
 func (connection *ZConnection) Begin() (err error) {
+	return connection.BeginContext(context.Background(), nil)
+}
+
+// BeginContext starts a transaction with ctx and opts, the transaction
+// is rolled back by the driver if ctx is done before Commit.
+// Inside a transaction it creates a savepoint, opts should be nil then.
+func (connection *ZConnection) BeginContext(ctx context.Context, opts *sql.TxOptions) (err error) {
 	if connection.connection == nil {
 		return errors.New("model has no db connection defined")
 	}
 
-	connection.transaction, err = connection.connection.Begin()
+	if connection.transaction != nil {
+		if opts != nil {
+			return errors.New("transaction options can not be applied to a savepoint")
+		}
+
+		savepoint := "zorm_sp_" + strconv.Itoa(len(connection.savepoints)+1)
+		if _, err = connection.transaction.ExecContext(ctx, "SAVEPOINT " + savepoint); err != nil {
+			return err
+		}
+
+		connection.savepoints = append(connection.savepoints, savepoint)
+		return nil
+	}
+
+	connection.transaction, err = connection.connection.BeginTx(ctx, opts)
 	return
 }
 
+// Rollback rolls back the innermost savepoint if any,
+// otherwise the whole transaction
 func (connection *ZConnection) Rollback() (error) {
+	return connection.RollbackContext(context.Background())
+}
+
+// RollbackContext is like Rollback, ctx is used for the savepoint
+func (connection *ZConnection) RollbackContext(ctx context.Context) (error) {
 	if connection.transaction == nil {
 		return errors.New("no transaction")
 	}
 
+	if n := len(connection.savepoints); n > 0 {
+		if _, err := connection.transaction.ExecContext(ctx, "ROLLBACK TO SAVEPOINT " + connection.savepoints[n-1]); err != nil {
+			return err
+		}
+		connection.savepoints = connection.savepoints[:n-1]
+		return nil
+	}
+
+	defer func() { connection.transaction = nil }()
 	return connection.transaction.Rollback()
 }
 
+// Commit releases the innermost savepoint if any,
+// otherwise commits the whole transaction
 func (connection *ZConnection) Commit() (error) {
+	return connection.CommitContext(context.Background())
+}
+
+// CommitContext is like Commit, ctx is used for the savepoint
+func (connection *ZConnection) CommitContext(ctx context.Context) (error) {
 	if connection.transaction == nil {
 		return errors.New("no transaction")
 	}
 
+	if n := len(connection.savepoints); n > 0 {
+		if _, err := connection.transaction.ExecContext(ctx, "RELEASE SAVEPOINT " + connection.savepoints[n-1]); err != nil {
+			return err
+		}
+		connection.savepoints = connection.savepoints[:n-1]
+		return nil
+	}
+
+	defer func() { connection.transaction = nil }()
 	return connection.transaction.Commit()
 }
 
+// Tx runs fn in a new transaction, fn receives a connection bound to
+// the transaction and its models run in the transaction.
+// The transaction is rolled back if fn returns an error or panics,
+// otherwise it is committed.
+func (connection *ZConnection) Tx(fn func(tx *ZConnection) error) (err error) {
+	return connection.TxContext(context.Background(), nil, fn)
+}
+
+// TxContext is like Tx but starts the transaction with ctx and opts.
+// Called on a connection with an open transaction, fn runs in a savepoint
+// of that transaction and opts should be nil.
+func (connection *ZConnection) TxContext(ctx context.Context, opts *sql.TxOptions, fn func(tx *ZConnection) error) (err error) {
+	var tx = connection
+	if connection.transaction == nil {
+		tx = &ZConnection{connection: connection.connection, dialect: connection.dialect}
+	}
+
+	if err = tx.BeginContext(ctx, opts); err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.RollbackContext(ctx)
+			panic(p)
+		}
+
+		if err != nil {
+			if rerr := tx.RollbackContext(ctx); rerr != nil {
+				err = errors.Wrap(err, "rollback failed: " + rerr.Error())
+			}
+			return
+		}
+
+		err = tx.CommitContext(ctx)
+	}()
+
+	return fn(tx)
+}
+
 // NewModel gives an z-model with current db connection
 func (connection *ZConnection) NewModel(table ZTable, sqlLogger zSqlLogger) (*zModel) {
 	var model = new(zModel)
 	model.table = table
 	model.sqlLogger = sqlLogger
+	model.dialect = connection.Dialect()
 	model.connect(connection.connection)
+	model.owner = connection
 
 	return model
 }
 
 type ZConnectionCfg struct {
+	Dialect 		string 		`json:"dialect"`
 	UserName 		string		`json:"user_name"`
 	Password 		string		`json:"password"`
 	Host 			string		`json:"host"`
@@ -0,0 +1,313 @@
+package zorm
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+// zTestDriver is a fake database/sql driver which records
+// every statement executed on the dsn it is opened with
+type zTestDriver struct {
+}
+
+var (
+	zTestLogMu 		sync.Mutex
+	zTestLogs 		= map[string][]string{}
+	zTestResults 	= map[string][][]driver.Value{}
+	// statements starting with the prefix fail on the dsn
+	zTestFailures 	= map[string]string{}
+)
+
+func init() {
+	sql.Register("zorm_test", new(zTestDriver))
+}
+
+func zTestLog(dsn, query string) {
+	zTestLogMu.Lock()
+	defer zTestLogMu.Unlock()
+	zTestLogs[dsn] = append(zTestLogs[dsn], query)
+}
+
+func zTestQueries(dsn string) []string {
+	zTestLogMu.Lock()
+	defer zTestLogMu.Unlock()
+	return append([]string(nil), zTestLogs[dsn]...)
+}
+
+func (d *zTestDriver) Open(dsn string) (driver.Conn, error) {
+	return &zTestConn{dsn: dsn}, nil
+}
+
+type zTestConn struct {
+	dsn 	string
+}
+
+func (conn *zTestConn) Prepare(query string) (driver.Stmt, error) {
+	return &zTestStmt{conn: conn, query: query}, nil
+}
+
+func (conn *zTestConn) Close() error {
+	return nil
+}
+
+func (conn *zTestConn) Begin() (driver.Tx, error) {
+	zTestLog(conn.dsn, "BEGIN")
+	return &zTestTx{conn: conn}, nil
+}
+
+type zTestTx struct {
+	conn 	*zTestConn
+}
+
+func (tx *zTestTx) Commit() error {
+	zTestLog(tx.conn.dsn, "COMMIT")
+	return nil
+}
+
+func (tx *zTestTx) Rollback() error {
+	zTestLog(tx.conn.dsn, "ROLLBACK")
+	return nil
+}
+
+type zTestStmt struct {
+	conn 	*zTestConn
+	query 	string
+}
+
+func (stmt *zTestStmt) Close() error {
+	return nil
+}
+
+func (stmt *zTestStmt) NumInput() int {
+	return -1
+}
+
+func (stmt *zTestStmt) Exec(args []driver.Value) (driver.Result, error) {
+	zTestLog(stmt.conn.dsn, stmt.query)
+
+	zTestLogMu.Lock()
+	prefix, fail := zTestFailures[stmt.conn.dsn]
+	zTestLogMu.Unlock()
+	if fail && strings.HasPrefix(stmt.query, prefix) {
+		return nil, errors.New("exec failed")
+	}
+
+	return zTestResult{}, nil
+}
+
+type zTestResult struct {
+}
+
+func (result zTestResult) LastInsertId() (int64, error) {
+	return 1, nil
+}
+
+func (result zTestResult) RowsAffected() (int64, error) {
+	return 1, nil
+}
+
+func (stmt *zTestStmt) Query(args []driver.Value) (driver.Rows, error) {
+	zTestLog(stmt.conn.dsn, stmt.query)
+
+	zTestLogMu.Lock()
+	values := zTestResults[stmt.conn.dsn]
+	zTestLogMu.Unlock()
+
+	columns := make([]string, 0)
+	if len(values) > 0 {
+		columns = zTestColumns(stmt.query)
+		if len(columns) != len(values[0]) {
+			columns = columns[:0]
+			for i := range values[0] {
+				columns = append(columns, fmt.Sprintf("c%d", i))
+			}
+		}
+	}
+	return &zTestRows{columns: columns, values: values}, nil
+}
+
+// zTestColumns gives the names of the selected columns of a simple select
+func zTestColumns(query string) []string {
+	var columns = make([]string, 0)
+	if !strings.HasPrefix(query, "SELECT ") || !strings.Contains(query, " FROM ") {
+		return columns
+	}
+
+	for _, column := range strings.Split(query[len("SELECT "):strings.Index(query, " FROM ")], ",") {
+		if idx := strings.Index(strings.ToUpper(column), " AS "); idx > 0 {
+			column = column[idx+4:]
+		} else if idx := strings.LastIndex(column, "."); idx > 0 {
+			column = column[idx+1:]
+		}
+		columns = append(columns, strings.Trim(strings.TrimSpace(column), "`\""))
+	}
+	return columns
+}
+
+type zTestRows struct {
+	columns 	[]string
+	values 		[][]driver.Value
+	idx 		int
+}
+
+func (rows *zTestRows) Columns() []string {
+	return rows.columns
+}
+
+func (rows *zTestRows) Close() error {
+	return nil
+}
+
+func (rows *zTestRows) Next(dest []driver.Value) error {
+	if rows.idx >= len(rows.values) {
+		return io.EOF
+	}
+	copy(dest, rows.values[rows.idx])
+	rows.idx++
+	return nil
+}
+
+func zTestConnection(t *testing.T, dsn string) *ZConnection {
+	db, err := sql.Open("zorm_test", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zTestLogMu.Lock()
+	delete(zTestLogs, dsn)
+	zTestLogMu.Unlock()
+
+	return &ZConnection{connection: db}
+}
+
+func TestZConnection_Tx(t *testing.T) {
+	var connection = zTestConnection(t, "tx_commit")
+
+	err := connection.Tx(func(tx *ZConnection) error {
+		_, err := tx.NewModel(new(zTestTable1), nil).Insert(&AssignList{"c1": "hhhh"})
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	queries := zTestQueries("tx_commit")
+	fmt.Println(queries)
+	if len(queries) != 3 || queries[0] != "BEGIN" || queries[2] != "COMMIT" {
+		t.Error("insert should run inside the transaction")
+	}
+}
+
+func TestZConnection_TxRollback(t *testing.T) {
+	var connection = zTestConnection(t, "tx_rollback")
+
+	err := connection.Tx(func(tx *ZConnection) error {
+		tx.NewModel(new(zTestTable1), nil).Insert(&AssignList{"c1": "hhhh"})
+		return errors.New("failed")
+	})
+	if err == nil {
+		t.Error("error should be returned")
+	}
+
+	func() {
+		defer func() {
+			if p := recover(); p == nil {
+				t.Error("panic should be propagated")
+			}
+		}()
+
+		connection.Tx(func(tx *ZConnection) error {
+			panic("failed")
+		})
+	}()
+
+	queries := zTestQueries("tx_rollback")
+	fmt.Println(queries)
+	if strings.Join(queries, ";") != "BEGIN;" + queries[1] + ";ROLLBACK;BEGIN;ROLLBACK" {
+		t.Error("transaction should be rolled back")
+	}
+}
+
+func TestZConnection_Savepoint(t *testing.T) {
+	var connection = zTestConnection(t, "tx_savepoint")
+
+	err := connection.Tx(func(tx *ZConnection) error {
+		tx.Tx(func(tx *ZConnection) error {
+			return nil
+		})
+
+		tx.Tx(func(tx *ZConnection) error {
+			return errors.New("failed")
+		})
+
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	queries := zTestQueries("tx_savepoint")
+	fmt.Println(queries)
+	if strings.Join(queries, ";") != "BEGIN;SAVEPOINT zorm_sp_1;RELEASE SAVEPOINT zorm_sp_1;" +
+		"SAVEPOINT zorm_sp_1;ROLLBACK TO SAVEPOINT zorm_sp_1;COMMIT" {
+		t.Error("nested transactions should use savepoints")
+	}
+}
+
+func TestZConnection_ModelAfterTx(t *testing.T) {
+	var connection = zTestConnection(t, "tx_model_after")
+
+	var model *zModel
+	err := connection.Tx(func(tx *ZConnection) error {
+		model = tx.NewModel(new(zTestTable1), nil)
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if _, err := model.Insert(&AssignList{"c1": "hhhh"}); err != nil {
+		t.Error("the model should run on the db after the transaction: " + err.Error())
+	}
+}
+
+func TestZConnection_SavepointFailure(t *testing.T) {
+	var connection = zTestConnection(t, "tx_savepoint_failure")
+	if err := connection.Begin(); err != nil {
+		t.Fatal(err)
+	}
+	if err := connection.BeginContext(context.Background(), &sql.TxOptions{ReadOnly: true}); err == nil {
+		t.Error("options should be refused for a savepoint")
+	}
+	if err := connection.Begin(); err != nil {
+		t.Fatal(err)
+	}
+
+	zTestFailures["tx_savepoint_failure"] = "RELEASE"
+	if err := connection.Commit(); err == nil || len(connection.savepoints) != 1 {
+		t.Error("a failed release should keep the savepoint")
+	}
+
+	delete(zTestFailures, "tx_savepoint_failure")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := connection.RollbackContext(ctx); err == nil || len(connection.savepoints) != 1 {
+		t.Error("a canceled context should fail the savepoint rollback")
+	}
+
+	if err := connection.Rollback(); err != nil || len(connection.savepoints) != 0 {
+		t.Error("the savepoint should be rolled back")
+	}
+	connection.Rollback()
+}
@@ -0,0 +1,175 @@
+package zorm
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// zCTE is a common table expression, used as a table by name
+// in the main query or in joins, the WITH clause is added
+// to the select query referencing it
+//
+//	WITH RECURSIVE tree AS (SELECT ... UNION ALL SELECT ... JOIN tree ...)
+//	SELECT ... FROM tree
+type zCTE struct {
+	name 		string
+	query 		string
+	args 		[]interface{}
+	columns 	[]string
+	primaryKey 	string
+	recursive 	bool
+	// the expressions referenced in the body
+	deps 		[]*zCTE
+}
+
+// With gives a common table expression named name with the current
+// query of the model selecting column, the columns of the table are
+// selected if no column is given
+func (model *zModel) With(name string, column ...string) (*zCTE, *zModelErr) {
+	if name == "" {
+		return nil, &zModelErr{err:errors.New("no name for the common table expression")}
+	}
+
+	var cte = &zCTE{name: name, primaryKey: model.table.PrimaryKey()}
+	query,args,err := cte.member(model, column)
+	if err != nil {
+		return nil, err
+	}
+
+	cte.query = query
+	cte.args = args
+	return cte, nil
+}
+
+// UnionAll adds the current query of the model as the recursive member
+// joined with UNION ALL, the model table may reference the expression itself
+func (cte *zCTE) UnionAll(model *zModel, column ...string) (*zCTE, *zModelErr) {
+	return cte.union(model, column, "UNION ALL")
+}
+
+// Union adds the recursive member joined with UNION
+func (cte *zCTE) Union(model *zModel, column ...string) (*zCTE, *zModelErr) {
+	return cte.union(model, column, "UNION")
+}
+
+func (cte *zCTE) union(model *zModel, column []string, union string) (*zCTE, *zModelErr) {
+	query,args,err := cte.member(model, column)
+	if err != nil {
+		return cte, err
+	}
+
+	cte.query = cte.query + " " + union + " " + query
+	cte.args = append(cte.args, args...)
+	cte.recursive = true
+	return cte, nil
+}
+
+// member builds a query of the body, the referenced expressions
+// other than cte itself are kept for the WITH clause
+func (cte *zCTE) member(model *zModel, column []string) (string, []interface{}, *zModelErr) {
+	var syntax = model.selectSyntax()
+	syntax.inCTE = true
+
+	if len(column) == 0 {
+		column = model.table.Columns().makeRow().columns
+	}
+
+	if err := model.checkStrict(column...); err != nil {
+		return "", nil, &zModelErr{err:err}
+	}
+
+	query,args,err := syntax.query(column...)
+	if err != nil {
+		return "", nil, &zModelErr{query:query, args:args, err:err}
+	}
+
+	if cte.columns == nil {
+		cte.columns = append([]string(nil), column...)
+		for _,expr := range syntax.exprs {
+			cte.columns = append(cte.columns, expr.column())
+		}
+	}
+
+	for _,dep := range tableCTEs(model.table) {
+		if dep != cte {
+			cte.deps = append(cte.deps, dep)
+		}
+	}
+
+	return query, args, nil
+}
+
+func (cte *zCTE) Table() (name string) {
+	return cte.name
+}
+
+func (cte *zCTE) PrimaryKey() string {
+	return cte.primaryKey
+}
+
+// Columns gives the selected columns by their names in the expression
+func (cte *zCTE) Columns() (*ZColumnList) {
+	return (&zSubQuery{columns: cte.columns}).Columns()
+}
+
+// SoftDelete is applied in the body already
+func (cte *zCTE) SoftDelete() SoftDelete {
+	return nil
+}
+
+// tableCTEs gives the common table expressions referenced by the table
+func tableCTEs(table ZTable) (ctes []*zCTE) {
+	switch t := table.(type) {
+	case *zCTE:
+		return []*zCTE{t}
+	case *ZJoinTable:
+		ctes = tableCTEs(t.TableReference)
+		for _,joined := range t.joinTables {
+			ctes = append(ctes, tableCTEs(joined)...)
+		}
+	}
+
+	return ctes
+}
+
+// withClause renders the expressions after the ones they reference,
+// each expression once
+func withClause(dialect Dialect, ctes []*zCTE) (query string, args []interface{}) {
+	if len(ctes) == 0 {
+		return "", nil
+	}
+
+	var seen = make(map[*zCTE]bool)
+	var ordered = make([]*zCTE, 0, len(ctes))
+	var visit func(cte *zCTE)
+	visit = func(cte *zCTE) {
+		if seen[cte] {
+			return
+		}
+		seen[cte] = true
+		for _,dep := range cte.deps {
+			visit(dep)
+		}
+		ordered = append(ordered, cte)
+	}
+	for _,cte := range ctes {
+		visit(cte)
+	}
+
+	var recursive bool
+	var parts = make([]string, 0, len(ordered))
+	args = make([]interface{}, 0)
+	for _,cte := range ordered {
+		recursive = recursive || cte.recursive
+		parts = append(parts, quoteColumn(dialect, cte.name) + " AS (" + cte.query + ")")
+		args = append(args, cte.args...)
+	}
+
+	query = "WITH "
+	if recursive {
+		query = "WITH RECURSIVE "
+	}
+
+	return query + strings.Join(parts, ", "), args
+}
@@ -0,0 +1,42 @@
+package zorm
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestZCTE(t *testing.T) {
+	var connection = zTestConnection(t, "model_cte")
+
+	var anchor = connection.NewModel(new(zTestTable1), nil)
+	anchor.NewQuery().WhereNull("c2")
+	tree, err := anchor.With("tree", "id", "c2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var member = &ZJoinTable{TableReference: new(zTestTable1), Alias: "t1"}
+	member.Join(tree, "t", JoinOn(WhereRaw("t1.c2 = t.id")))
+	if _, err := tree.UnionAll(connection.NewModel(member, nil), "t1.id", "t1.c2"); err != nil {
+		t.Fatal(err)
+	}
+
+	var model = connection.NewModel(tree, nil)
+	model.NewQuery().Where("id", ">", 1)
+	model.Get(&ZColumnList{"id": int64(0)})
+
+	var join = &ZJoinTable{TableReference: new(zTestTable2), Alias: "t2"}
+	join.LeftJoin(tree, "t", JoinOn(WhereRaw("t2.id = t.id")))
+	connection.NewModel(join, nil).Get(&ZColumnList{"t2.id": int64(0)})
+
+	queries := zTestQueries("model_cte")
+	fmt.Println(queries)
+	var with = "WITH RECURSIVE `tree` AS (SELECT `id`,`c2` FROM `test1` WHERE (`c2` IS NULL) UNION ALL " +
+		"SELECT `t1`.`id`,`t1`.`c2` FROM `test1` AS `t1`  INNER JOIN `tree` AS `t`  ON (t1.c2 = t.id))"
+	if queries[0] != with + " SELECT `id` FROM `tree` WHERE (`id` > ?)" {
+		t.Error("unexpected cte query: " + queries[0])
+	}
+	if queries[1] != with + " SELECT `t2`.`id` FROM `test2` AS `t2`  LEFT JOIN `tree` AS `t`  ON (t2.id = t.id) WHERE (`delete_time` = ?)" {
+		t.Error("unexpected cte join query: " + queries[1])
+	}
+}
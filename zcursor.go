@@ -0,0 +1,127 @@
+package zorm
+
+import (
+	"context"
+	"database/sql"
+)
+
+// zCursor scans the rows of a query one by one,
+// the row returned by Row is reused by the following Next
+type zCursor struct {
+	query 		string
+	args 		[]interface{}
+	sqlRows 	*sql.Rows
+	row 		*zRow
+	buffer 		[]interface{}
+	bufferPtr 	[]interface{}
+	err 		error
+	closed 		bool
+}
+
+// Next scans the next row, it returns false and closes the
+// cursor if there is no more row or an error occurs
+func (cursor *zCursor) Next() bool {
+	if cursor.closed {
+		return false
+	}
+
+	if !cursor.sqlRows.Next() {
+		cursor.err = cursor.sqlRows.Err()
+		cursor.Close()
+		return false
+	}
+
+	if err := cursor.sqlRows.Scan(cursor.bufferPtr...); err != nil {
+		cursor.err = err
+		cursor.Close()
+		return false
+	}
+
+	cursor.row.fillValues(cursor.buffer)
+	return true
+}
+
+// Row gives the current row, it is only valid until the next call of Next
+func (cursor *zCursor) Row() (*zRow) {
+	return cursor.row
+}
+
+func (cursor *zCursor) Err() (*zModelErr) {
+	if cursor.err == nil {
+		return nil
+	}
+
+	return &zModelErr{query:cursor.query, args:cursor.args, err:cursor.err}
+}
+
+// Close closes the underlying rows, it is safe to call Close more than once
+func (cursor *zCursor) Close() (error) {
+	if cursor.closed {
+		return nil
+	}
+
+	cursor.closed = true
+	return cursor.sqlRows.Close()
+}
+
+// Cursor runs the current query and gives a cursor scanning the rows lazily,
+// the cursor should be closed if it is not iterated to the end
+func (model *zModel) Cursor(column *ZColumnList) (*zCursor, *zModelErr) {
+	return model.CursorContext(context.Background(), column)
+}
+
+func (model *zModel) CursorContext(ctx context.Context, column *ZColumnList) (*zCursor, *zModelErr) {
+	var syntax = model.selectSyntax()
+
+	if column == nil {
+		column = model.table.Columns()
+	}
+	var row = column.makeRow()
+	if err := model.checkStrict(row.columns...); err != nil {
+		return nil, &zModelErr{err:err}
+	}
+	query,args,err := syntax.query(row.columns...)
+	for _,expr := range syntax.exprs {
+		row.columns = append(row.columns, expr.column())
+		row.value = append(row.value, nil)
+	}
+	if err != nil {
+		return nil, &zModelErr{query:query, args:args, err:err}
+	}
+
+	sqlRows,qerr := model.queryRows(ctx, query, args...)
+	if qerr != nil {
+		return nil, qerr
+	}
+
+	var cursor = &zCursor{query:query, args:args, sqlRows:sqlRows, row:row}
+	cursor.buffer = make([]interface{}, len(row.columns))
+	cursor.bufferPtr = make([]interface{}, len(row.columns))
+	for i := range cursor.buffer {
+		cursor.bufferPtr[i] = &cursor.buffer[i]
+	}
+
+	return cursor, nil
+}
+
+// Each calls fn with the rows of the current query one by one,
+// it stops at the first error returned by fn
+func (model *zModel) Each(column *ZColumnList, fn func(row *zRow) error) (*zModelErr) {
+	return model.EachContext(context.Background(), column, fn)
+}
+
+func (model *zModel) EachContext(ctx context.Context, column *ZColumnList, fn func(row *zRow) error) (*zModelErr) {
+	cursor, err := model.CursorContext(ctx, column)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	for cursor.Next() {
+		if ferr := fn(cursor.Row()); ferr != nil {
+			return &zModelErr{query:cursor.query, args:cursor.args, err:ferr}
+		}
+	}
+
+	return cursor.Err()
+}
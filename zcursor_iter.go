@@ -0,0 +1,39 @@
+//go:build go1.23
+
+package zorm
+
+import (
+	"context"
+	"iter"
+)
+
+// Iter gives an iterator over the rows of the current query,
+// the rows are always closed when the loop ends
+//
+//	for row, err := range model.Iter(nil) {
+//		...
+//	}
+func (model *zModel) Iter(column *ZColumnList) iter.Seq2[*zRow, error] {
+	return model.IterContext(context.Background(), column)
+}
+
+func (model *zModel) IterContext(ctx context.Context, column *ZColumnList) iter.Seq2[*zRow, error] {
+	return func(yield func(*zRow, error) bool) {
+		cursor, err := model.CursorContext(ctx, column)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer cursor.Close()
+
+		for cursor.Next() {
+			if !yield(cursor.Row(), nil) {
+				return
+			}
+		}
+
+		if err := cursor.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
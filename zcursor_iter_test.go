@@ -0,0 +1,29 @@
+//go:build go1.23
+
+package zorm
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"testing"
+)
+
+func TestZModel_Iter(t *testing.T) {
+	var connection = zTestConnection(t, "model_iter")
+	zTestResults["model_iter"] = [][]driver.Value{{[]byte("a")}, {[]byte("b")}, {[]byte("c")}}
+
+	var model = connection.NewModel(new(zTestTable1), nil)
+	var count = 0
+	for row, err := range model.Iter(&ZColumnList{"c1": ""}) {
+		if err != nil {
+			t.Error(err)
+			break
+		}
+		fmt.Println(row.Get("c1"))
+		count++
+	}
+
+	if count != 3 {
+		t.Error("unexpected iter result")
+	}
+}
@@ -0,0 +1,33 @@
+package zorm
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestZModel_Each(t *testing.T) {
+	var connection = zTestConnection(t, "model_each")
+	zTestResults["model_each"] = [][]driver.Value{{[]byte("a")}, {[]byte("b")}, {[]byte("c")}}
+
+	var model = connection.NewModel(new(zTestTable1), nil)
+	var names = make([]interface{}, 0)
+	err := model.Each(&ZColumnList{"c1": ""}, func(row *zRow) error {
+		v, _ := row.Get("c1")
+		names = append(names, v)
+		if len(names) == 2 {
+			return errors.New("stop")
+		}
+		return nil
+	})
+	if err == nil || err.Error() != "stop" {
+		t.Error("each should stop at the callback error")
+	}
+
+	fmt.Println(names)
+	if len(names) != 2 || names[1] != "b" {
+		t.Error("unexpected each result")
+	}
+}
@@ -0,0 +1,270 @@
+package zorm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type DialectFeature int
+
+const (
+	// INSERT INTO t SET a=?,b=?
+	FeatureInsertSet DialectFeature = iota
+	// UPDATE ... ORDER BY ... LIMIT ?
+	FeatureUpdateLimit
+	// DELETE FROM t ... ORDER BY ... LIMIT ?
+	FeatureDeleteLimit
+	// DELETE t1,t2 FROM t1 JOIN t2 ...
+	FeatureMultiDelete
+	// sql.Result.LastInsertId
+	FeatureLastInsertId
+	// INSERT ... RETURNING
+	FeatureReturning
+	// INSERT ... ON DUPLICATE KEY UPDATE
+	FeatureOnDuplicateKey
+	// INSERT ... ON CONFLICT DO NOTHING / DO UPDATE
+	FeatureOnConflict
+	// REPLACE INTO
+	FeatureReplace
+	// (SELECT ...) UNION (SELECT ...)
+	FeatureCompoundParens
+	// SELECT ... FOR UPDATE / FOR SHARE
+	FeatureRowLock
+)
+
+type Dialect interface {
+	// dialect name, used in ZConnectionCfg
+	Name() string
+
+	// database/sql driver name
+	DriverName() string
+
+	// DSN builds the data source name from the connection config
+	DSN(cfg ZConnectionCfg) string
+
+	// Placeholder returns the bind variable of the n-th (1-based) arg
+	Placeholder(n int) string
+
+	// Quote quotes a single identifier
+	Quote(identifier string) string
+
+	// Limit returns the LIMIT clause body and its args,
+	// offset is omitted if withOffset is false
+	Limit(rowCount, offset int64, withOffset bool) (query string, args []interface{})
+
+	// Supports reports whether the dialect supports the feature
+	Supports(feature DialectFeature) bool
+
+	// MaxPlaceholders returns the max number of bind variables
+	// allowed in one statement
+	MaxPlaceholders() int
+}
+
+var (
+	dialectsMu 		sync.RWMutex
+	dialects 		= map[string]Dialect{}
+	defaultDialect 	Dialect = new(MySQLDialect)
+)
+
+func init() {
+	RegisterDialect(new(MySQLDialect))
+	RegisterDialect(new(PostgresDialect))
+	RegisterDialect(new(SQLiteDialect))
+}
+
+// RegisterDialect makes a dialect available by its name
+func RegisterDialect(dialect Dialect) {
+	if dialect == nil {
+		return
+	}
+
+	dialectsMu.Lock()
+	defer dialectsMu.Unlock()
+	dialects[dialect.Name()] = dialect
+}
+
+// GetDialect returns the dialect registered with name,
+// empty name gives the default mysql dialect
+func GetDialect(name string) (Dialect, bool) {
+	if name == "" {
+		return defaultDialect, true
+	}
+
+	dialectsMu.RLock()
+	defer dialectsMu.RUnlock()
+	dialect, ok := dialects[strings.ToLower(name)]
+	return dialect, ok
+}
+
+func useDialect(dialect Dialect) Dialect {
+	if dialect == nil {
+		return defaultDialect
+	}
+	return dialect
+}
+
+// rebind replaces the ? bind variables outside of quoted
+// strings with the dialect placeholders
+func rebind(dialect Dialect, query string) string {
+	dialect = useDialect(dialect)
+	if dialect.Placeholder(1) == "?" || !strings.Contains(query, "?") {
+		return query
+	}
+
+	var builder strings.Builder
+	var quote byte = 0
+	var n = 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '?':
+			n++
+			builder.WriteString(dialect.Placeholder(n))
+			continue
+		}
+		builder.WriteByte(c)
+	}
+
+	return builder.String()
+}
+
+func ansiLimit(rowCount, offset int64, withOffset bool) (query string, args []interface{}) {
+	if !withOffset {
+		return "?", []interface{}{rowCount}
+	}
+	return "? OFFSET ?", []interface{}{rowCount, offset}
+}
+
+// mysql dialect
+type MySQLDialect struct {
+}
+
+func (dialect *MySQLDialect) Name() string {
+	return "mysql"
+}
+
+func (dialect *MySQLDialect) DriverName() string {
+	return "mysql"
+}
+
+func (dialect *MySQLDialect) DSN(cfg ZConnectionCfg) string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8&timeout=%ds&readTimeout=%ds&writeTimeout=%ds",
+		cfg.UserName, cfg.Password, cfg.Host, cfg.Port, cfg.DbName, cfg.TimeoutSec, cfg.ReadTimeoutSec, cfg.WriteTimeoutSec)
+}
+
+func (dialect *MySQLDialect) Placeholder(n int) string {
+	return "?"
+}
+
+func (dialect *MySQLDialect) Quote(identifier string) string {
+	return "`" + strings.Replace(identifier, "`", "``", -1) + "`"
+}
+
+func (dialect *MySQLDialect) Limit(rowCount, offset int64, withOffset bool) (query string, args []interface{}) {
+	return ansiLimit(rowCount, offset, withOffset)
+}
+
+func (dialect *MySQLDialect) Supports(feature DialectFeature) bool {
+	switch feature {
+	case FeatureInsertSet, FeatureUpdateLimit, FeatureDeleteLimit, FeatureMultiDelete, FeatureLastInsertId,
+		FeatureOnDuplicateKey, FeatureReplace, FeatureCompoundParens, FeatureRowLock:
+		return true
+	}
+	return false
+}
+
+func (dialect *MySQLDialect) MaxPlaceholders() int {
+	return 65535
+}
+
+// postgresql dialect, the driver (e.g. github.com/lib/pq)
+// should be imported by the caller
+type PostgresDialect struct {
+}
+
+func (dialect *PostgresDialect) Name() string {
+	return "postgres"
+}
+
+func (dialect *PostgresDialect) DriverName() string {
+	return "postgres"
+}
+
+func (dialect *PostgresDialect) DSN(cfg ZConnectionCfg) string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s connect_timeout=%d",
+		cfg.Host, cfg.Port, cfg.UserName, cfg.Password, cfg.DbName, cfg.TimeoutSec)
+}
+
+func (dialect *PostgresDialect) Placeholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+func (dialect *PostgresDialect) Quote(identifier string) string {
+	return `"` + strings.Replace(identifier, `"`, `""`, -1) + `"`
+}
+
+func (dialect *PostgresDialect) Limit(rowCount, offset int64, withOffset bool) (query string, args []interface{}) {
+	return ansiLimit(rowCount, offset, withOffset)
+}
+
+func (dialect *PostgresDialect) Supports(feature DialectFeature) bool {
+	switch feature {
+	case FeatureReturning, FeatureOnConflict, FeatureCompoundParens, FeatureRowLock:
+		return true
+	}
+	return false
+}
+
+func (dialect *PostgresDialect) MaxPlaceholders() int {
+	return 65535
+}
+
+// sqlite dialect, the driver (e.g. github.com/mattn/go-sqlite3)
+// should be imported by the caller
+type SQLiteDialect struct {
+}
+
+func (dialect *SQLiteDialect) Name() string {
+	return "sqlite"
+}
+
+func (dialect *SQLiteDialect) DriverName() string {
+	return "sqlite3"
+}
+
+// DSN uses DbName as the database file
+func (dialect *SQLiteDialect) DSN(cfg ZConnectionCfg) string {
+	return cfg.DbName
+}
+
+func (dialect *SQLiteDialect) Placeholder(n int) string {
+	return "?"
+}
+
+func (dialect *SQLiteDialect) Quote(identifier string) string {
+	return `"` + strings.Replace(identifier, `"`, `""`, -1) + `"`
+}
+
+func (dialect *SQLiteDialect) Limit(rowCount, offset int64, withOffset bool) (query string, args []interface{}) {
+	return ansiLimit(rowCount, offset, withOffset)
+}
+
+func (dialect *SQLiteDialect) Supports(feature DialectFeature) bool {
+	switch feature {
+	case FeatureLastInsertId, FeatureReturning, FeatureOnConflict, FeatureReplace:
+		return true
+	}
+	return false
+}
+
+func (dialect *SQLiteDialect) MaxPlaceholders() int {
+	return 999
+}
@@ -0,0 +1,60 @@
+package zorm
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRebind(t *testing.T) {
+	var query = "SELECT c1 FROM test1 WHERE (c1 = ?) AND (c2 LIKE '%?%') AND (c3 IN (?,?))"
+
+	fmt.Println(rebind(new(MySQLDialect), query))
+	fmt.Println(rebind(new(PostgresDialect), query))
+
+	if q := rebind(new(PostgresDialect), query); q != "SELECT c1 FROM test1 WHERE (c1 = $1) AND (c2 LIKE '%?%') AND (c3 IN ($2,$3))" {
+		t.Error("unexpected rebind result: " + q)
+	}
+}
+
+func TestGetDialect(t *testing.T) {
+	for _, name := range []string{"", "mysql", "postgres", "sqlite"} {
+		dialect, ok := GetDialect(name)
+		if !ok {
+			t.Error("dialect not found: " + name)
+			continue
+		}
+		fmt.Println(dialect.Name(), dialect.DriverName(), dialect.Quote("c1"))
+	}
+
+	if _, ok := GetDialect("oracle"); ok {
+		t.Error("oracle dialect should not be registered")
+	}
+}
+
+func TestPostgresInsertSyntax(t *testing.T) {
+	var syntax = new(zInsert)
+	syntax.table = new(zTestTable1)
+	syntax.dialect = new(PostgresDialect)
+	syntax.assigns = AssignList{"c1": "hhhh"}
+
+	query, args, err := syntax.query()
+	if err != nil {
+		t.Error(err)
+	}
+
+	fmt.Println(rebind(syntax.dialect, query), args)
+}
+
+func TestPostgresUpdateLimit(t *testing.T) {
+	var syntax = new(zUpdate)
+	syntax.table = new(zTestTable1)
+	syntax.dialect = new(PostgresDialect)
+	syntax.assigns = AssignList{"c1": "xxxxx"}
+	syntax.limit = new(zLimit).Limit(10)
+
+	if _, _, err := syntax.query(); err == nil {
+		t.Error("update limit should not be supported by postgres")
+	} else {
+		fmt.Println(err)
+	}
+}
@@ -0,0 +1,117 @@
+package zorm
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// zColumnExpr is a select expression with its bound args
+type zColumnExpr struct {
+	expr 		string
+	alias 		string
+	args 		[]interface{}
+	// the expression is the call of the function name with
+	// params if name is set, modifier is put before the params
+	name 		string
+	modifier 	string
+	params 		[]zColumnExpr
+	// the invalid function name, returned by the syntax query
+	err 		error
+}
+
+func (expr zColumnExpr) column() string {
+	return expr.render(nil, false)
+}
+
+// quoted renders the expression with the alias quoted by the dialect,
+// the expression itself is quoted only if it is a column
+func (expr zColumnExpr) quoted(dialect Dialect) string {
+	return expr.render(dialect, true)
+}
+
+func (expr zColumnExpr) render(dialect Dialect, quote bool) string {
+	var text = expr.expr
+	if expr.name != "" {
+		var params = make([]string, 0, len(expr.params))
+		for _,param := range expr.params {
+			params = append(params, param.render(dialect, quote))
+		}
+		text = expr.name + "(" + expr.modifier + strings.Join(params, ",") + ")"
+	} else if quote {
+		text = quoteColumn(dialect, text)
+	}
+
+	switch {
+	case expr.alias == "":
+		return text
+	case quote:
+		return text + " AS " + useDialect(dialect).Quote(expr.alias)
+	}
+	return text + " AS " + expr.alias
+}
+
+// As gives a copy of the expression selected as alias
+func (expr zColumnExpr) As(alias string) zColumnExpr {
+	expr.alias = alias
+	return expr
+}
+
+// Raw gives an expression with the args bound to the placeholders
+//
+//	Raw("CASE WHEN c2 > ? THEN 1 ELSE 0 END", 10).As("big")
+func Raw(expr string, args ...interface{}) zColumnExpr {
+	return zColumnExpr{expr: expr, args: args}
+}
+
+// Func gives the call of the sql function name with the expressions
+//
+//	Func("CONCAT", Raw("c1"), Raw("?", "-"), Raw("c3"))
+func Func(name string, arg ...zColumnExpr) zColumnExpr {
+	var expr = zColumnExpr{name: name, params: arg, args: make([]interface{}, 0)}
+	if !ValidIdentifier(name) {
+		expr.err = errors.Errorf("invalid function %q", name)
+	}
+	for _,a := range arg {
+		expr.args = append(expr.args, a.args...)
+		if expr.err == nil {
+			expr.err = a.err
+		}
+	}
+
+	return expr
+}
+
+// call gives the call of the aggregate function name on the column
+func call(name, modifier, column string) zColumnExpr {
+	return zColumnExpr{name: name, modifier: modifier, params: []zColumnExpr{{expr: column}}}
+}
+
+func Count(column string) zColumnExpr {
+	return call("COUNT", "", column)
+}
+
+func CountDistinct(column string) zColumnExpr {
+	return call("COUNT", "DISTINCT ", column)
+}
+
+func Sum(column string) zColumnExpr {
+	return call("SUM", "", column)
+}
+
+func Avg(column string) zColumnExpr {
+	return call("AVG", "", column)
+}
+
+func Min(column string) zColumnExpr {
+	return call("MIN", "", column)
+}
+
+func Max(column string) zColumnExpr {
+	return call("MAX", "", column)
+}
+
+// Coalesce gives column, or value if column is NULL
+func Coalesce(column string, value interface{}) zColumnExpr {
+	return Func("COALESCE", zColumnExpr{expr: column}, Raw("?", value))
+}
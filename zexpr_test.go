@@ -0,0 +1,51 @@
+package zorm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestZColumnExpr(t *testing.T) {
+	var expr = Func("CONCAT", Raw("c1"), Raw("?", "-"), Coalesce("c3", "none")).As("label")
+	fmt.Println(expr.column(), expr.args)
+	if expr.column() != "CONCAT(c1,?,COALESCE(c3,?)) AS label" || len(expr.args) != 2 {
+		t.Error("unexpected function expression: " + expr.column())
+	}
+
+	var connection = zTestConnection(t, "model_expr")
+	var model = connection.NewModel(new(zTestTable1), nil)
+	model.NewQuery().Distinct().
+		Select(CountDistinct("c2").As("n"), Raw("CASE WHEN c4 > ? THEN 1 ELSE 0 END", 10).As("big")).
+		Where("c1", "=", "a").GroupBy("c1")
+	model.Get(&ZColumnList{"c1": ""})
+	model.Paginate(1, 10, &ZColumnList{"c1": ""})
+
+	queries := zTestQueries("model_expr")
+	fmt.Println(queries)
+	if queries[0] != "SELECT DISTINCT `c1`,COUNT(DISTINCT `c2`) AS `n`,CASE WHEN c4 > ? THEN 1 ELSE 0 END AS `big` FROM `test1` WHERE (`c1` = ?) GROUP BY `c1`" {
+		t.Error("unexpected expression query: " + queries[0])
+	}
+	if queries[1] != "SELECT count(1) as total FROM (" + queries[0] + ") AS zorm_count" {
+		t.Error("unexpected distinct count query: " + queries[1])
+	}
+
+	model.NewQuery().Distinct().Where("c1", "=", "a")
+	model.Count()
+	queries = zTestQueries("model_expr")
+	if !strings.HasPrefix(queries[len(queries)-1], "SELECT count(1) as total FROM (SELECT DISTINCT ") {
+		t.Error("distinct rows should be counted: " + queries[len(queries)-1])
+	}
+
+	model.NewQuery().Distinct().Select(Max("c2").As("m"), Coalesce("t.c3", 0)).Where("c1", "=", "a")
+	model.First(&ZColumnList{"c1": ""})
+	queries = zTestQueries("model_expr")
+	if last := queries[len(queries)-1]; last != "SELECT DISTINCT `c1`,MAX(`c2`) AS `m`,COALESCE(`t`.`c3`,?) FROM `test1` WHERE (`c1` = ?) LIMIT ? OFFSET ?" {
+		t.Error("unexpected first query: " + last)
+	}
+
+	model.NewQuery().Select(Func("SLEEP(1);--", Raw("c1")))
+	if _, err := model.Get(nil); err == nil {
+		t.Error("the function name should be validated")
+	}
+}
@@ -0,0 +1,249 @@
+package zorm
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var zIdentifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// the comparison operations accepted by Where in strict mode
+var zStrictOperations = map[string]bool{
+	"=": true, "!=": true, "<>": true, "<": true, "<=": true, ">": true, ">=": true,
+	"<=>": true, "IS": true, "IS NOT": true, "LIKE": true, "NOT LIKE": true,
+}
+
+// ValidIdentifier tells if name is a plain column or table name,
+// optionally qualified by a table alias as in t.name
+func ValidIdentifier(name string) bool {
+	return zIdentifierPattern.MatchString(name)
+}
+
+// ValidateColumn gives an error if column is not a valid identifier,
+// or not one of allowed if allowed is given
+//
+//	if err := ValidateColumn(req.Sort, "id", "name", "create_time"); err != nil {...}
+func ValidateColumn(column string, allowed ...string) error {
+	if !ValidIdentifier(column) {
+		return errors.Errorf("invalid column %q", column)
+	}
+
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	for _,a := range allowed {
+		if a == column {
+			return nil
+		}
+	}
+
+	return errors.Errorf("unknown column %q", column)
+}
+
+// QuoteIdentifier quotes the parts of a qualified identifier
+// by the dialect, `t`.`name` for MySQL, * is left as it is
+func QuoteIdentifier(dialect Dialect, identifier string) string {
+	var parts = strings.Split(identifier, ".")
+	for i,part := range parts {
+		if part != "*" {
+			parts[i] = useDialect(dialect).Quote(part)
+		}
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// quoteColumn quotes a column by the dialect, t.* and a column aliased
+// as in "t.name AS alias" are quoted by parts, an expression is left
+// as it is
+func quoteColumn(dialect Dialect, column string) string {
+	if column == "*" {
+		return column
+	}
+
+	if ValidIdentifier(column) {
+		return QuoteIdentifier(dialect, column)
+	}
+
+	if strings.HasSuffix(column, ".*") && ValidIdentifier(column[:len(column)-2]) {
+		return QuoteIdentifier(dialect, column)
+	}
+
+	if idxAs := strings.Index(strings.ToUpper(column), " AS "); idxAs > 0 {
+		name, alias := column[:idxAs], column[idxAs+4:]
+		if ValidIdentifier(name) && ValidIdentifier(alias) && !strings.Contains(alias, ".") {
+			return QuoteIdentifier(dialect, name) + " AS " + useDialect(dialect).Quote(alias)
+		}
+	}
+
+	return column
+}
+
+// quoteIdentifiers quotes every identifier by QuoteIdentifier
+func quoteIdentifiers(dialect Dialect, identifiers []string) []string {
+	var quoted = make([]string, 0, len(identifiers))
+	for _,identifier := range identifiers {
+		quoted = append(quoted, QuoteIdentifier(dialect, identifier))
+	}
+
+	return quoted
+}
+
+// quoteColumns quotes every column by quoteColumn
+func quoteColumns(dialect Dialect, columns []string) []string {
+	var quoted = make([]string, 0, len(columns))
+	for _,column := range columns {
+		quoted = append(quoted, quoteColumn(dialect, column))
+	}
+
+	return quoted
+}
+
+// Quote quotes the identifier by the dialect of the model
+func (model *zModel) Quote(identifier string) string {
+	return QuoteIdentifier(model.dialect, identifier)
+}
+
+// Strict makes the model refuse a query with columns in Where, OrderBy,
+// GroupBy, the selected or the assigned columns which are neither columns
+// of the table nor in allowed, or with an unknown Where operation
+func (model *zModel) Strict(allowed ...string) (*zModel) {
+	model.strict = true
+	model.allowedColumns = allowed
+	return model
+}
+
+// strictCheck gives the check of a column against the columns of the
+// table, the allowed columns and the aliases of the selected expressions
+func (model *zModel) strictCheck() func(column string) error {
+	var known = make([]string, 0)
+	if tableColumns := model.table.Columns(); tableColumns != nil {
+		for column := range *tableColumns {
+			known = append(known, column)
+		}
+	}
+	known = append(known, model.allowedColumns...)
+	if model.query != nil {
+		for _,expr := range model.query.columns {
+			if expr.alias != "" {
+				known = append(known, expr.alias)
+			}
+		}
+	}
+
+	return func(column string) error {
+		if err := ValidateColumn(column, known...); err != nil {
+			// a column qualified by the table alias
+			if idxDot := strings.Index(column, "."); idxDot > 0 && ValidIdentifier(column) {
+				return ValidateColumn(column[idxDot+1:], known...)
+			}
+			return err
+		}
+		return nil
+	}
+}
+
+// checkStrictColumns checks columns, the selected or assigned columns
+// of the statement, in strict mode, a selected column may be aliased.
+// It is used by the statements not built from the current query.
+func (model *zModel) checkStrictColumns(columns ...string) error {
+	if !model.strict {
+		return nil
+	}
+
+	var check = model.strictCheck()
+	for _,column := range columns {
+		if idxAs := strings.Index(strings.ToUpper(column), " AS "); idxAs > 0 {
+			column = column[:idxAs]
+		}
+		if err := check(column); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkStrict checks columns and the columns of the current query in
+// strict mode. The raw conditions are not checked.
+func (model *zModel) checkStrict(columns ...string) error {
+	if err := model.checkStrictColumns(columns...); err != nil {
+		return err
+	}
+	if !model.strict || model.query == nil {
+		return nil
+	}
+
+	var check = model.strictCheck()
+	if model.query.where != nil {
+		if err := model.query.where.checkColumns(check); err != nil {
+			return err
+		}
+	}
+
+	if model.query.orderBy != nil {
+		for _,item := range model.query.orderBy.items() {
+			if err := check(item.column); err != nil {
+				return err
+			}
+		}
+	}
+
+	if model.query.groupBy != nil {
+		for _,column := range model.query.groupBy.columns {
+			if err := check(column); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkColumns checks the columns and operations of the conditions
+func (where *zWhere) checkColumns(check func(column string) error) error {
+	for _,cond := range where.cond {
+		switch cond.operation {
+		case "RAW", "EXISTS", "NOT EXISTS":
+			continue
+		case "WHERE", "NOT":
+			if err := cond.value.(*zWhere).checkColumns(check); err != nil {
+				return err
+			}
+			continue
+		case "COLUMN":
+			compare := cond.value.(zColumnCompare)
+			if !zStrictOperations[strings.ToUpper(compare.operation)] {
+				return errors.Errorf("invalid operation %q", compare.operation)
+			}
+			if err := check(compare.column); err != nil {
+				return err
+			}
+		case "TUPLE":
+			tuple := cond.value.(zTuple)
+			if tuple.operation != "IN" && !zStrictOperations[strings.ToUpper(tuple.operation)] {
+				return errors.Errorf("invalid operation %q", tuple.operation)
+			}
+			for _,column := range tuple.columns {
+				if err := check(column); err != nil {
+					return err
+				}
+			}
+			continue
+		case "IN", "NOT IN", "LIKE", "RANGE", "NOT RANGE", "IN SUB", "NULL", "NOT NULL":
+		default:
+			if !zStrictOperations[strings.ToUpper(cond.operation)] {
+				return errors.Errorf("invalid operation %q", cond.operation)
+			}
+		}
+
+		if err := check(cond.column); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
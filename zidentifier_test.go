@@ -0,0 +1,123 @@
+package zorm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestQuoteIdentifier(t *testing.T) {
+	fmt.Println(QuoteIdentifier(nil, "t.c1"), QuoteIdentifier(new(PostgresDialect), "t.*"))
+	if QuoteIdentifier(nil, "t.c`1") != "`t`.`c``1`" || QuoteIdentifier(new(PostgresDialect), "t.*") != `"t".*` {
+		t.Error("unexpected quoted identifier")
+	}
+}
+
+func TestValidateColumn(t *testing.T) {
+	for _, column := range []string{"id; DROP TABLE test1", "c1 DESC", "(SELECT 1)", ""} {
+		if ValidateColumn(column) == nil {
+			t.Error("invalid column should be refused: " + column)
+		}
+	}
+
+	if ValidateColumn("t.c1") != nil || ValidateColumn("c1", "c1", "c2") != nil || ValidateColumn("c3", "c1", "c2") == nil {
+		t.Error("unexpected column validation")
+	}
+}
+
+func TestZModel_Strict(t *testing.T) {
+	var connection = zTestConnection(t, "model_strict")
+	var model = connection.NewModel(new(zTestTable1), nil).Strict("score")
+
+	model.NewQuery().Where("t.c1", "=", "a").OrderBy("score", "desc").GroupBy("c2")
+	if _, err := model.Get(nil); err != nil {
+		t.Error(err)
+	}
+
+	model.NewQuery().OrderBy("c9", "ASC")
+	if _, err := model.Get(nil); err == nil {
+		t.Error("unknown order column should be refused")
+	}
+
+	model.NewQuery().Where("c1", "= 1 OR 1 =", 1)
+	if _, err := model.Count(); err == nil {
+		t.Error("unknown operation should be refused")
+	} else {
+		fmt.Println(err)
+	}
+
+	model.NewQuery().WhereAnd(WhereIn("c1; --", 1))
+	if _, err := model.Delete(); err == nil {
+		t.Error("invalid where column should be refused")
+	}
+
+	model.NewQuery()
+	if _, err := model.Get(&ZColumnList{"c9": ""}); err == nil {
+		t.Error("unknown selected column should be refused")
+	}
+	if _, err := model.Update(&AssignList{"c1=c1,c2": 1}); err == nil {
+		t.Error("unknown assigned column should be refused")
+	}
+	if _, err := model.Sum("c1) FROM test1; --"); err == nil {
+		t.Error("invalid aggregate column should be refused")
+	}
+
+	if len(zTestQueries("model_strict")) != 1 {
+		t.Error("refused queries should not be run")
+	}
+
+	// the statements not built from the query ignore the invalid query left
+	model.NewQuery().OrderBy("c9", "ASC")
+	if _, err := model.Find(1, &ZColumnList{"c1": ""}); err != nil {
+		t.Error(err)
+	}
+	if _, err := model.FindMany([]int64{1, 2}, &ZColumnList{"c1": ""}); err != nil {
+		t.Error(err)
+	}
+	if _, err := model.DeleteByKey(1); err != nil {
+		t.Error(err)
+	}
+	if _, err := model.Insert(&AssignList{"c1": "a"}); err != nil {
+		t.Error(err)
+	}
+	if _, err := model.ForceDelete(); err == nil {
+		t.Error("the invalid query should be refused by delete")
+	}
+	if len(zTestQueries("model_strict")) != 5 {
+		t.Error("the statements by key should be run")
+	}
+}
+
+func TestZModel_Quote(t *testing.T) {
+	var connection = zTestConnection(t, "model_quote")
+	connection.dialect = new(PostgresDialect)
+	var model = connection.NewModel(new(zTestTable1), nil)
+
+	model.NewQuery().Where("t.c1", "=", "a").OrderBy("c2", "DESC").GroupBy("c2")
+	if _, err := model.Get(&ZColumnList{"c2": "", "count(1) AS n": int64(0)}); err != nil {
+		t.Error(err)
+	}
+	model.NewQuery().Where("c2", "=", 1)
+	if _, err := model.Update(&AssignList{"c1": "b"}); err != nil {
+		t.Error(err)
+	}
+
+	model.NewQuery().OrderBy("c1; DROP TABLE test1", "ASC")
+	if _, err := model.Get(nil); err == nil {
+		t.Error("invalid order by column should be refused")
+	}
+	model.NewQuery().OrderBy("c1", "ASC, c2")
+	if _, err := model.Get(nil); err == nil {
+		t.Error("invalid order by direction should be refused")
+	}
+	model.NewQuery().GroupBy("c1", "sleep(10)")
+	if _, err := model.Get(nil); err == nil {
+		t.Error("invalid group by column should be refused")
+	}
+
+	queries := zTestQueries("model_quote")
+	if len(queries) != 2 || !strings.HasSuffix(queries[0], ` FROM "test1" WHERE ("t"."c1" = $1) GROUP BY "c2" ORDER BY "c2" DESC`) ||
+		queries[1] != `UPDATE "test1" SET "c1"=$1 WHERE ("c2" = $2)` {
+		t.Error("unexpected quoted queries", queries)
+	}
+}
@@ -0,0 +1,363 @@
+package zorm
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// zField is a struct field mapped to a column by the zcolumn tag
+//
+//	Name 	string 	`zcolumn:"name"`
+//	Email 	string 	`zcolumn:"email,omitempty"`
+//	Cache 	string 	`zcolumn:"-"`
+//
+// fields of embedded structs without a zcolumn tag are flattened,
+// unexported fields are ignored
+type zField struct {
+	column 		string
+	index 		[]int
+	typ 		reflect.Type
+	omitEmpty 	bool
+}
+
+var zFieldsCache sync.Map
+
+var (
+	zTimeType 	= reflect.TypeOf(time.Time{})
+	zTimeLayouts = []string{"2006-01-02 15:04:05", time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02"}
+)
+
+// structFields gives the mapped fields of the struct type
+func structFields(t reflect.Type) []zField {
+	if fields, ok := zFieldsCache.Load(t); ok {
+		return fields.([]zField)
+	}
+
+	var fields = make([]zField, 0)
+	var seen = make(map[string]bool)
+	collectFields(t, nil, &fields, seen)
+
+	zFieldsCache.Store(t, fields)
+	return fields
+}
+
+func collectFields(t reflect.Type, index []int, fields *[]zField, seen map[string]bool) {
+	var embedded = make([]reflect.StructField, 0)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, tagged := field.Tag.Lookup("zcolumn")
+
+		if field.Anonymous && !tagged {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, field)
+			}
+			continue
+		}
+
+		if !tagged || field.PkgPath != "" {
+			continue
+		}
+
+		column, omitEmpty := parseColumnTag(tag)
+		if column == "" || seen[column] {
+			continue
+		}
+
+		seen[column] = true
+		*fields = append(*fields, zField{
+			column: column,
+			index: append(append([]int(nil), index...), i),
+			typ: field.Type,
+			omitEmpty: omitEmpty,
+		})
+	}
+
+	// the outer fields hide the fields of embedded structs
+	for _, field := range embedded {
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			if field.PkgPath != "" {
+				continue
+			}
+			ft = ft.Elem()
+		}
+		collectFields(ft, append(append([]int(nil), index...), field.Index...), fields, seen)
+	}
+}
+
+// parseColumnTag parses `zcolumn:"name,omitempty"`, "-" gives an empty column
+func parseColumnTag(tag string) (column string, omitEmpty bool) {
+	parts := strings.Split(tag, ",")
+	column = strings.TrimSpace(parts[0])
+	if column == "-" {
+		return "", false
+	}
+
+	for _, option := range parts[1:] {
+		if strings.TrimSpace(option) == "omitempty" {
+			omitEmpty = true
+		}
+	}
+
+	return column, omitEmpty
+}
+
+// fieldByIndex walks the index of a struct value, nil embedded pointers
+// are allocated if alloc is true, otherwise ok is false
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (field reflect.Value, ok bool) {
+	for i, idx := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(idx)
+	}
+
+	return v, true
+}
+
+// assignValue sets value to dest, converting between compatible types
+func assignValue(dest reflect.Value, value interface{}) error {
+	if !dest.CanSet() {
+		return errors.Errorf("can not set %s", dest.Type())
+	}
+
+	if value == nil {
+		dest.Set(reflect.Zero(dest.Type()))
+		return nil
+	}
+
+	var src = reflect.ValueOf(value)
+	if src.Type().AssignableTo(dest.Type()) {
+		dest.Set(src)
+		return nil
+	}
+
+	if scanner, ok := dest.Addr().Interface().(sql.Scanner); ok {
+		if valuer, ok := value.(driver.Valuer); ok {
+			v, err := valuer.Value()
+			if err != nil {
+				return err
+			}
+			return scanner.Scan(v)
+		}
+		return scanner.Scan(value)
+	}
+
+	if valuer, ok := value.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil {
+			return err
+		}
+		return assignValue(dest, v)
+	}
+
+	if dest.Kind() == reflect.Ptr {
+		elem := reflect.New(dest.Type().Elem())
+		if err := assignValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		dest.Set(elem)
+		return nil
+	}
+
+	if src.Kind() == reflect.Ptr {
+		if src.IsNil() {
+			dest.Set(reflect.Zero(dest.Type()))
+			return nil
+		}
+		return assignValue(dest, src.Elem().Interface())
+	}
+
+	switch dest.Kind() {
+	case reflect.String:
+		switch v := value.(type) {
+		case []byte:
+			dest.SetString(string(v))
+			return nil
+		case time.Time:
+			dest.SetString(v.Format("2006-01-02 15:04:05"))
+			return nil
+		}
+
+		switch src.Kind() {
+		case reflect.String:
+			dest.SetString(src.String())
+			return nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			dest.SetString(strconv.FormatInt(src.Int(), 10))
+			return nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			dest.SetString(strconv.FormatUint(src.Uint(), 10))
+			return nil
+		case reflect.Float32, reflect.Float64:
+			dest.SetString(strconv.FormatFloat(src.Float(), 'f', -1, 64))
+			return nil
+		case reflect.Bool:
+			dest.SetString(strconv.FormatBool(src.Bool()))
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := asInt64(src)
+		if err != nil {
+			return err
+		}
+		if dest.OverflowInt(n) {
+			return errors.Errorf("value %d overflows %s", n, dest.Type())
+		}
+		dest.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := asInt64(src)
+		if err != nil {
+			return err
+		}
+		if n < 0 || dest.OverflowUint(uint64(n)) {
+			return errors.Errorf("value %d overflows %s", n, dest.Type())
+		}
+		dest.SetUint(uint64(n))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := asFloat64(src)
+		if err != nil {
+			return err
+		}
+		if dest.OverflowFloat(f) {
+			return errors.Errorf("value %v overflows %s", f, dest.Type())
+		}
+		dest.SetFloat(f)
+		return nil
+	case reflect.Bool:
+		b, err := asBool(src)
+		if err != nil {
+			return err
+		}
+		dest.SetBool(b)
+		return nil
+	case reflect.Slice:
+		if dest.Type().Elem().Kind() == reflect.Uint8 {
+			switch v := value.(type) {
+			case string:
+				dest.SetBytes([]byte(v))
+				return nil
+			case []byte:
+				dest.SetBytes(append([]byte(nil), v...))
+				return nil
+			}
+		}
+	case reflect.Struct:
+		if dest.Type() == zTimeType {
+			var s string
+			switch v := value.(type) {
+			case string:
+				s = v
+			case []byte:
+				s = string(v)
+			}
+
+			if s != "" {
+				for _, layout := range zTimeLayouts {
+					if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+						dest.Set(reflect.ValueOf(t))
+						return nil
+					}
+				}
+				return errors.Errorf("can not parse %q as time", s)
+			}
+		}
+	}
+
+	if src.Kind() == dest.Kind() && src.Type().ConvertibleTo(dest.Type()) {
+		dest.Set(src.Convert(dest.Type()))
+		return nil
+	}
+
+	return errors.Errorf("can not convert %T to %s", value, dest.Type())
+}
+
+func asInt64(src reflect.Value) (int64, error) {
+	switch src.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return src.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n := src.Uint()
+		if n > 1<<63-1 {
+			return 0, errors.Errorf("value %d overflows int64", n)
+		}
+		return int64(n), nil
+	case reflect.Float32, reflect.Float64:
+		f := src.Float()
+		if f != float64(int64(f)) {
+			return 0, errors.Errorf("value %v is not an integer", f)
+		}
+		return int64(f), nil
+	case reflect.Bool:
+		if src.Bool() {
+			return 1, nil
+		}
+		return 0, nil
+	case reflect.String:
+		return strconv.ParseInt(strings.TrimSpace(src.String()), 10, 64)
+	case reflect.Slice:
+		if src.Type().Elem().Kind() == reflect.Uint8 {
+			return strconv.ParseInt(strings.TrimSpace(string(src.Bytes())), 10, 64)
+		}
+	}
+
+	return 0, errors.Errorf("can not convert %s to integer", src.Type())
+}
+
+func asFloat64(src reflect.Value) (float64, error) {
+	switch src.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(src.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(src.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return src.Float(), nil
+	case reflect.String:
+		return strconv.ParseFloat(strings.TrimSpace(src.String()), 64)
+	case reflect.Slice:
+		if src.Type().Elem().Kind() == reflect.Uint8 {
+			return strconv.ParseFloat(strings.TrimSpace(string(src.Bytes())), 64)
+		}
+	}
+
+	return 0, errors.Errorf("can not convert %s to float", src.Type())
+}
+
+func asBool(src reflect.Value) (bool, error) {
+	switch src.Kind() {
+	case reflect.Bool:
+		return src.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return src.Int() != 0, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return src.Uint() != 0, nil
+	case reflect.String:
+		return strconv.ParseBool(strings.TrimSpace(src.String()))
+	case reflect.Slice:
+		if src.Type().Elem().Kind() == reflect.Uint8 {
+			return strconv.ParseBool(strings.TrimSpace(string(src.Bytes())))
+		}
+	}
+
+	return false, errors.Errorf("can not convert %s to bool", src.Type())
+}
@@ -0,0 +1,91 @@
+package zorm
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testBaseStruct struct {
+	Id 			int64 		`zcolumn:"id"`
+	CreateTime 	time.Time 	`zcolumn:"create_time"`
+}
+
+type testMapperStruct struct {
+	testBaseStruct
+	Name 		string 			`zcolumn:"name"`
+	Age 		int32 			`zcolumn:"age"`
+	Score 		float32 		`zcolumn:"score,omitempty"`
+	Email 		*string 		`zcolumn:"email"`
+	Phone 		sql.NullString 	`zcolumn:"phone"`
+	Cache 		string 			`zcolumn:"-"`
+	secret 		string 			`zcolumn:"secret"`
+}
+
+func TestStructFields(t *testing.T) {
+	var fields = structFields(reflect.TypeOf(testMapperStruct{}))
+
+	var columns = make([]string, 0)
+	for _, field := range fields {
+		columns = append(columns, field.column)
+	}
+	fmt.Println(columns)
+
+	if len(columns) != 7 {
+		t.Error("unexpected mapped columns")
+	}
+}
+
+func TestZRow_BindConvert(t *testing.T) {
+	var row = &zRow{filledMap: ZMap{
+		"id": []byte("12"),
+		"create_time": "2019-01-29 10:00:00",
+		"name": []byte("zorm"),
+		"age": int64(18),
+		"score": "99.5",
+		"email": []byte("z@orm"),
+		"phone": nil,
+		"secret": "secret",
+	}}
+
+	var obj testMapperStruct
+	if err := row.Bind(&obj); err != nil {
+		t.Error(err)
+	}
+	fmt.Printf("%+v\n", obj)
+
+	if obj.Id != 12 || obj.Age != 18 || obj.Score != 99.5 || obj.Name != "zorm" ||
+		obj.Email == nil || *obj.Email != "z@orm" || obj.Phone.Valid || obj.secret != "" ||
+		obj.CreateTime.Format("2006-01-02 15:04:05") != "2019-01-29 10:00:00" {
+		t.Error("unexpected bind result")
+	}
+
+	row.filledMap["age"] = int64(1) << 40
+	if err := row.Bind(&obj); err == nil {
+		t.Error("overflow should fail")
+	} else {
+		fmt.Println(err)
+	}
+
+	if err := row.Bind(obj); err == nil {
+		t.Error("non pointer should fail")
+	}
+}
+
+func TestAssignList_Bind(t *testing.T) {
+	var email = "z@orm"
+	var obj = testMapperStruct{Name: "zorm", Email: &email, Cache: "cache"}
+	obj.Id = 10
+
+	var list = AssignList{}.Bind(&obj)
+	fmt.Println(*list)
+
+	if _, ok := (*list)["score"]; ok {
+		t.Error("omitempty column should be skipped")
+	}
+	if _, ok := (*list)["id"]; !ok {
+		t.Error("embedded column should be assigned")
+	}
+}
@@ -1,21 +1,39 @@
 package zorm
 
 import (
+	"context"
 	"database/sql"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/pkg/errors"
 	"reflect"
+	"strings"
 )
 
 type AssignList map[string]interface{}
 
+// Bind assigns the zcolumn tagged fields of the struct,
+// zero fields tagged with omitempty are skipped
 func (list AssignList) Bind(obj interface{}) (*AssignList) {
-	var r = reflect.ValueOf(obj).Elem()
-	var numFields = r.NumField()
+	return list.bind(obj, false)
+}
+
+// bind assigns the zcolumn tagged fields of the struct, all of the mapped
+// columns are assigned if all is true, the fields under a nil embedded
+// pointer are assigned as NULL then
+func (list AssignList) bind(obj interface{}, all bool) (*AssignList) {
+	var r = reflect.Indirect(reflect.ValueOf(obj))
+	if r.Kind() != reflect.Struct {
+		return &list
+	}
 
-	for i := 0; i < numFields; i++ {
-		if fk,ok := r.Type().Field(i).Tag.Lookup("zcolumn"); ok {
-			list.Assign(fk, r.Field(i).Interface())
+	for _, field := range structFields(r.Type()) {
+		v, ok := fieldByIndex(r, field.index, false)
+		switch {
+		case all && !ok:
+			list.Assign(field.column, nil)
+		case !ok, !all && field.omitEmpty && v.IsZero():
+		default:
+			list.Assign(field.column, v.Interface())
 		}
 	}
 	return &list
@@ -35,49 +53,64 @@ type zModel struct {
 	table 		ZTable
 	query 		*zQueryBuilder
 	connection 	*sql.DB
+	// the connection the model is created by, its open
+	// transaction is taken when a query runs
+	owner 		*ZConnection
+	dialect 	Dialect
+	batchSize 	int
+	// strict mode refuses unknown columns in the query
+	strict 			bool
+	allowedColumns 	[]string
 
 	sqlLogger 	zSqlLogger
 }
 
+// the default max rows of one multiple rows insert statement
+const zDefaultBatchSize = 1000
+
 func (model *zModel) connect(connection *sql.DB) {
 	model.connection = connection
 }
 
+// BatchSize sets the max rows of one statement in InsertMany,
+// lower it if the rows are too large for max_allowed_packet
+func (model *zModel) BatchSize(rows int) (*zModel) {
+	model.batchSize = rows
+	return model
+}
+
 func (model *zModel) NewQuery() (*zQueryBuilder) {
 	model.query = new(zQueryBuilder)
 	return model.query
 }
 
 func (model *zModel) Get(column *ZColumnList) (*zRows, *zModelErr) {
-	var syntax = new(zSelect)
-	syntax.table = model.table
-
-	if model.query != nil {
-		syntax.groupby = model.query.groupBy
-		syntax.orderby = model.query.orderBy
-		syntax.limit = model.query.limit
-		syntax.where = model.query.where
-	} else {
-		syntax.groupby = nil
-		syntax.orderby = nil
-		syntax.limit = nil
-		syntax.where = nil
-	}
+	return model.GetContext(context.Background(), column)
+}
 
-	if model.table.SoftDelete() != nil {
-		syntax.where = new(zWhere).Where(model.table.SoftDelete().Column(), "=", model.table.SoftDelete().Value()).AndWhere(syntax.where)
-	}
+func (model *zModel) GetContext(ctx context.Context, column *ZColumnList) (*zRows, *zModelErr) {
+	return model.selectRows(ctx, model.selectSyntax(), column)
+}
 
+// selectRows runs the select syntax and fills all the rows
+func (model *zModel) selectRows(ctx context.Context, syntax *zSelect, column *ZColumnList) (*zRows, *zModelErr) {
 	if column == nil {
 		column = model.table.Columns()
 	}
 	var rows = column.makeRows()
+	if err := model.checkStrict(rows.columns...); err != nil {
+		return nil, &zModelErr{err:err}
+	}
 	query,args,err := syntax.query(rows.columns...)
+	for _,expr := range syntax.exprs {
+		rows.columns = append(rows.columns, expr.column())
+		rows.value = append(rows.value, nil)
+	}
 	if err != nil {
 		return nil, &zModelErr{query:query, args:args, err:err}
 	}
 
-	sqlRows,qerr := model.queryRows(query, args...)
+	sqlRows,qerr := model.queryRows(ctx, query, args...)
 	if qerr != nil {
 		return nil, qerr
 	}
@@ -89,34 +122,235 @@ func (model *zModel) Get(column *ZColumnList) (*zRows, *zModelErr) {
 	return rows, nil
 }
 
-func (model *zModel) First(column *ZColumnList) (*zRow, *zModelErr) {
+// selectSyntax gives the select syntax of the current query
+// with the soft delete condition
+func (model *zModel) selectSyntax() (*zSelect) {
 	var syntax = new(zSelect)
 	syntax.table = model.table
+	syntax.dialect = model.dialect
 
 	if model.query != nil {
 		syntax.groupby = model.query.groupBy
+		syntax.orderby = model.query.orderBy
 		syntax.limit = model.query.limit
 		syntax.where = model.query.where
-	} else {
-		syntax.groupby = nil
-		syntax.limit = nil
-		syntax.where = nil
+		syntax.exprs = model.query.columns
+		syntax.distinct = model.query.distinct
+		syntax.lock = model.query.lock
 	}
+	syntax.transaction = model.tx() != nil
 
 	if model.table.SoftDelete() != nil {
 		syntax.where = new(zWhere).Where(model.table.SoftDelete().Column(), "=", model.table.SoftDelete().Value()).AndWhere(syntax.where)
 	}
 
+	return syntax
+}
+
+// GetInto scans the rows of the current query into dest, a pointer to
+// a slice of structs or struct pointers. The selected columns are
+// taken from the zcolumn tags of the struct.
+func (model *zModel) GetInto(dest interface{}) (*zModelErr) {
+	return model.GetIntoContext(context.Background(), dest)
+}
+
+func (model *zModel) GetIntoContext(ctx context.Context, dest interface{}) (*zModelErr) {
+	if serr := model.checkStrict(); serr != nil {
+		return &zModelErr{err:serr}
+	}
+
+	_, err := model.selectInto(ctx, model.selectSyntax(), dest)
+	return err
+}
+
+// FirstInto scans the first row of the current query into dest,
+// a pointer to struct, found is false if there is no row
+func (model *zModel) FirstInto(dest interface{}) (found bool, err *zModelErr) {
+	return model.FirstIntoContext(context.Background(), dest)
+}
+
+func (model *zModel) FirstIntoContext(ctx context.Context, dest interface{}) (found bool, err *zModelErr) {
+	if serr := model.checkStrict(); serr != nil {
+		return false, &zModelErr{err:serr}
+	}
+
+	var syntax = model.selectSyntax()
+	var limit = new(zLimit).Limit(1)
+	if syntax.limit != nil {
+		limit.Offset(syntax.limit.iOffset)
+	}
+	syntax.limit = limit
+
+	count, err := model.selectInto(ctx, syntax, dest)
+	return count > 0, err
+}
+
+// FindInto scans the row with the primary key id into dest,
+// a pointer to struct, found is false if there is no such row
+func (model *zModel) FindInto(id interface{}, dest interface{}) (found bool, err *zModelErr) {
+	return model.FindIntoContext(context.Background(), id, dest)
+}
+
+func (model *zModel) FindIntoContext(ctx context.Context, id interface{}, dest interface{}) (found bool, err *zModelErr) {
+	var syntax = new(zSelect)
+	syntax.table = model.table
+	syntax.dialect = model.dialect
+	where,kerr := keyWhere(primaryKeys(model.table), id)
+	if kerr != nil {
+		return false, &zModelErr{err:kerr}
+	}
+	syntax.where = where
+	if model.table.SoftDelete() != nil {
+		syntax.where.Where(model.table.SoftDelete().Column(), "=", model.table.SoftDelete().Value())
+	}
+	syntax.limit = new(zLimit).Limit(1).Offset(0)
+
+	count, err := model.selectInto(ctx, syntax, dest)
+	return count > 0, err
+}
+
+// selectInto runs the select syntax and scans the rows into dest, which is
+// a pointer to a slice of structs (or struct pointers) or a pointer to struct
+// taking the first row only
+func (model *zModel) selectInto(ctx context.Context, syntax *zSelect, dest interface{}) (count int, err *zModelErr) {
+	var r = reflect.ValueOf(dest)
+	if r.Kind() != reflect.Ptr || r.IsNil() {
+		return 0, &zModelErr{err:errors.New("you should scan into a pointer")}
+	}
+
+	var slice reflect.Value
+	var elemType = r.Elem().Type()
+	var ptrElem = false
+	if elemType.Kind() == reflect.Slice {
+		slice = r.Elem()
+		elemType = elemType.Elem()
+		if elemType.Kind() == reflect.Ptr {
+			ptrElem = true
+			elemType = elemType.Elem()
+		}
+	}
+	if elemType.Kind() != reflect.Struct {
+		return 0, &zModelErr{err:errors.New("you should scan into a struct or a slice of struct")}
+	}
+
+	var fields = structFields(elemType)
+	if len(fields) == 0 {
+		return 0, &zModelErr{err:errors.New("no zcolumn tagged field")}
+	}
+	var columns = make([]string, len(fields))
+	for i, field := range fields {
+		columns[i] = field.column
+	}
+
+	if serr := model.checkStrictColumns(columns...); serr != nil {
+		return 0, &zModelErr{err:serr}
+	}
+	query,args,serr := syntax.query(columns...)
+	if serr != nil {
+		return 0, &zModelErr{query:query, args:args, err:serr}
+	}
+
+	sqlRows,err := model.queryRows(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer sqlRows.Close()
+
+	// the returned columns are matched with the fields by name,
+	// so that the select expressions can be scanned as well
+	names, serr := sqlRows.Columns()
+	if serr != nil {
+		return 0, &zModelErr{query:query, args:args, err:serr}
+	}
+	var fieldOf = make([]int, len(names))
+	for i, name := range names {
+		fieldOf[i] = -1
+		for j, field := range fields {
+			if field.column == name || field.column[strings.LastIndex(field.column, ".")+1:] == name {
+				fieldOf[i] = j
+				break
+			}
+		}
+	}
+
+	var values = make([]interface{}, len(names))
+	var valuePtr = make([]interface{}, len(names))
+	for i := range values {
+		valuePtr[i] = &values[i]
+	}
+
+	if slice.IsValid() {
+		slice.Set(slice.Slice(0, 0))
+	}
+	for sqlRows.Next() {
+		if serr = sqlRows.Scan(valuePtr...); serr != nil {
+			return count, &zModelErr{query:query, args:args, err:serr}
+		}
+
+		var obj reflect.Value
+		if slice.IsValid() {
+			obj = reflect.New(elemType).Elem()
+		} else {
+			obj = r.Elem()
+		}
+
+		for i, j := range fieldOf {
+			if j < 0 {
+				continue
+			}
+			fieldValue, _ := fieldByIndex(obj, fields[j].index, true)
+			if serr = assignValue(fieldValue, values[i]); serr != nil {
+				return count, &zModelErr{query:query, args:args, err:errors.Wrapf(serr, "scan column %s", fields[j].column)}
+			}
+		}
+		count++
+
+		if !slice.IsValid() {
+			break
+		}
+		if ptrElem {
+			slice.Set(reflect.Append(slice, obj.Addr()))
+		} else {
+			slice.Set(reflect.Append(slice, obj))
+		}
+	}
+
+	if serr = sqlRows.Err(); serr != nil {
+		return count, &zModelErr{query:query, args:args, err:serr}
+	}
+
+	return count, nil
+}
+
+func (model *zModel) First(column *ZColumnList) (*zRow, *zModelErr) {
+	return model.FirstContext(context.Background(), column)
+}
+
+func (model *zModel) FirstContext(ctx context.Context, column *ZColumnList) (*zRow, *zModelErr) {
+	var syntax = model.selectSyntax()
+	var limit = new(zLimit).Limit(1)
+	if syntax.limit != nil {
+		limit.Offset(syntax.limit.iOffset)
+	}
+	syntax.limit = limit
+
 	if column == nil {
 		column = model.table.Columns()
 	}
 	var row = column.makeRow()
+	if err := model.checkStrict(row.columns...); err != nil {
+		return nil, &zModelErr{err:err}
+	}
 	query,args,err := syntax.query(row.columns...)
+	for _,expr := range syntax.exprs {
+		row.columns = append(row.columns, expr.column())
+		row.value = append(row.value, nil)
+	}
 	if err != nil {
 		return nil, &zModelErr{query:query, args:args, err:err}
 	}
 
-	sqlRow,qerr := model.queryRow(query, args...)
+	sqlRow,qerr := model.queryRow(ctx, query, args...)
 	if qerr != nil {
 		return nil, qerr
 	}
@@ -128,10 +362,21 @@ func (model *zModel) First(column *ZColumnList) (*zRow, *zModelErr) {
 	return row,nil
 }
 
-func (model *zModel) Find(id int64, column *ZColumnList) (*zRow, *zModelErr) {
+// Find gives the row with the primary key id, the key value may be of
+// any type, for a ZCompositeKeyTable it is a slice of the key values
+func (model *zModel) Find(id interface{}, column *ZColumnList) (*zRow, *zModelErr) {
+	return model.FindContext(context.Background(), id, column)
+}
+
+func (model *zModel) FindContext(ctx context.Context, id interface{}, column *ZColumnList) (*zRow, *zModelErr) {
 	var syntax = new(zSelect)
 	syntax.table = model.table
-	syntax.where = new(zWhere).Where(model.table.PrimaryKey(), "=", id)
+	syntax.dialect = model.dialect
+	where,kerr := keyWhere(primaryKeys(model.table), id)
+	if kerr != nil {
+		return nil, &zModelErr{err:kerr}
+	}
+	syntax.where = where
 	if syntax.table.SoftDelete() != nil {
 		syntax.where.Where(syntax.table.SoftDelete().Column(), "=", syntax.table.SoftDelete().Value())
 	}
@@ -141,12 +386,15 @@ func (model *zModel) Find(id int64, column *ZColumnList) (*zRow, *zModelErr) {
 		column = model.table.Columns()
 	}
 	var row = column.makeRow()
+	if err := model.checkStrictColumns(row.columns...); err != nil {
+		return nil, &zModelErr{err:err}
+	}
 	query,args,err := syntax.query(row.columns...)
 	if err != nil {
 		return nil, &zModelErr{query:query, args:args, err:err}
 	}
 
-	sqlRow,qerr := model.queryRow(query, args...)
+	sqlRow,qerr := model.queryRow(ctx, query, args...)
 	if qerr != nil {
 		return nil, qerr
 	}
@@ -158,31 +406,46 @@ func (model *zModel) Find(id int64, column *ZColumnList) (*zRow, *zModelErr) {
 	return row, nil
 }
 
-func (model *zModel) FindMany(id []int64, column *ZColumnList) (*zRows, *zModelErr) {
+// FindMany gives the rows with the primary keys in id, a slice of keys such as
+// []int64 or []string, for a ZCompositeKeyTable each key is a slice of the
+// values of the key columns, (a,b) IN ((?,?),...) is used then
+func (model *zModel) FindMany(id interface{}, column *ZColumnList) (*zRows, *zModelErr) {
+	return model.FindManyContext(context.Background(), id, column)
+}
+
+func (model *zModel) FindManyContext(ctx context.Context, id interface{}, column *ZColumnList) (*zRows, *zModelErr) {
 	var syntax = new(zSelect)
 	syntax.table = model.table
+	syntax.dialect = model.dialect
 
-	primaryIds := make([]interface{}, len(id))
-	for i,v := range id {
-		primaryIds[i] = v
+	var keys = primaryKeys(model.table)
+	where,kerr := keysWhere(keys, id)
+	if kerr != nil {
+		return nil, &zModelErr{err:kerr}
 	}
-	syntax.where = new(zWhere).In(model.table.PrimaryKey(), primaryIds...)
+	syntax.where = where
 	if model.table.SoftDelete() != nil {
 		syntax.where.Where(model.table.SoftDelete().Column(), "=", model.table.SoftDelete().Value())
 	}
-	syntax.orderby = new(zOrderBy).OrderBy(model.table.PrimaryKey(), "ASC")
-	syntax.limit = new(zLimit).Limit(int64(len(primaryIds))).Offset(0)
+	syntax.orderby = new(zOrderBy)
+	for _,key := range keys {
+		syntax.orderby.OrderBy(key, "ASC")
+	}
+	syntax.limit = new(zLimit).Limit(int64(reflect.ValueOf(id).Len())).Offset(0)
 
 	if column == nil {
 		column = model.table.Columns()
 	}
 	var rows = column.makeRows()
+	if err := model.checkStrictColumns(rows.columns...); err != nil {
+		return nil, &zModelErr{err:err}
+	}
 	query,args,err := syntax.query(rows.columns...)
 	if err != nil {
 		return nil,&zModelErr{query:query, args:args, err:err}
 	}
 
-	sqlRows,qerr := model.queryRows(query, args...)
+	sqlRows,qerr := model.queryRows(ctx, query, args...)
 	if qerr != nil {
 		return nil, qerr
 	}
@@ -195,20 +458,127 @@ func (model *zModel) FindMany(id []int64, column *ZColumnList) (*zRows, *zModelE
 }
 
 func (model *zModel) Insert(list *AssignList) (id int64, err *zModelErr) {
+	return model.InsertContext(context.Background(), list)
+}
+
+func (model *zModel) InsertContext(ctx context.Context, list *AssignList) (id int64, err *zModelErr) {
 	var syntax = new(zInsert)
 	syntax.table = model.table
+	syntax.dialect = model.dialect
 	syntax.assigns = *list
 
+	return model.insert(ctx, syntax)
+}
+
+// Upsert inserts the row or updates updateColumns of the existing row
+// (ON DUPLICATE KEY UPDATE on mysql, ON CONFLICT on the primary key on
+// postgres and sqlite). All assigned columns except the primary key are
+// updated if no updateColumns is given, the existing row is kept as it is
+// if no column is left.
+func (model *zModel) Upsert(list *AssignList, updateColumns ...string) (id int64, err *zModelErr) {
+	return model.UpsertContext(context.Background(), list, updateColumns...)
+}
+
+func (model *zModel) UpsertContext(ctx context.Context, list *AssignList, updateColumns ...string) (id int64, err *zModelErr) {
+	return model.UpsertOnContext(ctx, list, nil, updateColumns...)
+}
+
+// UpsertOn is like Upsert but the conflict target of ON CONFLICT is the
+// unique columns conflict instead of the primary key, the conflict
+// columns are not updated by default. Mysql updates on a conflict of
+// any unique key, conflict is only excluded from the updates there.
+func (model *zModel) UpsertOn(list *AssignList, conflict []string, updateColumns ...string) (id int64, err *zModelErr) {
+	return model.UpsertOnContext(context.Background(), list, conflict, updateColumns...)
+}
+
+func (model *zModel) UpsertOnContext(ctx context.Context, list *AssignList, conflict []string, updateColumns ...string) (id int64, err *zModelErr) {
+	var syntax = new(zInsert)
+	syntax.table = model.table
+	syntax.dialect = model.dialect
+	syntax.assigns = *list
+	syntax.conflict = conflict
+
+	if len(updateColumns) == 0 {
+		var keys = primaryKeys(model.table)
+		for _,column := range assignColumns(syntax.assigns) {
+			if !inStrings(keys, column) && !inStrings(conflict, column) {
+				updateColumns = append(updateColumns, column)
+			}
+		}
+	}
+
+	if len(updateColumns) == 0 {
+		syntax.keepExisting = true
+	} else {
+		syntax.updates = updateColumns
+	}
+
+	return model.insert(ctx, syntax)
+}
+
+// InsertIgnore inserts the row and ignores duplicate key errors
+func (model *zModel) InsertIgnore(list *AssignList) (id int64, err *zModelErr) {
+	return model.InsertIgnoreContext(context.Background(), list)
+}
+
+func (model *zModel) InsertIgnoreContext(ctx context.Context, list *AssignList) (id int64, err *zModelErr) {
+	var syntax = new(zInsert)
+	syntax.table = model.table
+	syntax.dialect = model.dialect
+	syntax.assigns = *list
+	syntax.ignore = true
+
+	return model.insert(ctx, syntax)
+}
+
+// Replace deletes the existing row with the same key and inserts the row
+func (model *zModel) Replace(list *AssignList) (id int64, err *zModelErr) {
+	return model.ReplaceContext(context.Background(), list)
+}
+
+func (model *zModel) ReplaceContext(ctx context.Context, list *AssignList) (id int64, err *zModelErr) {
+	var syntax = new(zInsert)
+	syntax.table = model.table
+	syntax.dialect = model.dialect
+	syntax.assigns = *list
+	syntax.replace = true
+
+	return model.insert(ctx, syntax)
+}
+
+func (model *zModel) insert(ctx context.Context, syntax *zInsert) (id int64, err *zModelErr) {
+	if serr := model.checkStrictColumns(append(append(assignColumns(syntax.assigns), syntax.updates...), syntax.conflict...)...); serr != nil {
+		return 0, &zModelErr{err:serr}
+	}
+
+	// the insert id is taken by RETURNING without LastInsertId,
+	// a key which is not an integer or not a single column gives 0,
+	// see InsertKey
+	var dialect = useDialect(model.dialect)
+	if !dialect.Supports(FeatureLastInsertId) && dialect.Supports(FeatureReturning) && len(primaryKeys(model.table)) == 1 {
+		key,err := model.insertReturning(ctx, syntax)
+		if err != nil || key == nil {
+			return 0, err
+		}
+
+		id,_ = asInt64(reflect.ValueOf(key))
+		return id, nil
+	}
+
 	query,args,serr := syntax.query()
 	if serr != nil {
 		return 0, &zModelErr{query:query, args:args, err:serr}
 	}
 
-	result,err := model.exec(query, args...)
+	result,err := model.exec(ctx, query, args...)
 	if err != nil {
 		return 0, err
 	}
 
+	if !dialect.Supports(FeatureLastInsertId) {
+		return 0, nil
+	}
+
 	id, serr = result.LastInsertId()
 	if serr != nil {
 		return id, &zModelErr{query:query, args:args, err:serr}
@@ -217,9 +587,222 @@ func (model *zModel) Insert(list *AssignList) (id int64, err *zModelErr) {
 	return id,nil
 }
 
+// InsertKey inserts the row and gives its primary key, which is a slice of
+// the key values for a ZCompositeKeyTable. The key is taken from list if
+// all key columns are assigned, otherwise by LastInsertId for a single key
+// or by RETURNING if the dialect supports it.
+func (model *zModel) InsertKey(list *AssignList) (key interface{}, err *zModelErr) {
+	return model.InsertKeyContext(context.Background(), list)
+}
+
+func (model *zModel) InsertKeyContext(ctx context.Context, list *AssignList) (key interface{}, err *zModelErr) {
+	var syntax = new(zInsert)
+	syntax.table = model.table
+	syntax.dialect = model.dialect
+	syntax.assigns = *list
+
+	var keys = primaryKeys(model.table)
+	if len(keys) == 0 {
+		return nil, &zModelErr{err:errors.New("no primary key defined")}
+	}
+
+	var values = make([]interface{}, 0, len(keys))
+	for _,column := range keys {
+		if v, ok := syntax.assigns[column]; ok {
+			values = append(values, v)
+		}
+	}
+
+	if serr := model.checkStrictColumns(assignColumns(syntax.assigns)...); serr != nil {
+		return nil, &zModelErr{err:serr}
+	}
+
+	var dialect = useDialect(model.dialect)
+	switch {
+	case len(values) == len(keys):
+		query,args,serr := syntax.query()
+		if serr != nil {
+			return nil, &zModelErr{query:query, args:args, err:serr}
+		}
+
+		if _,err := model.exec(ctx, query, args...); err != nil {
+			return nil, err
+		}
+
+		if len(keys) == 1 {
+			return values[0], nil
+		}
+		return values, nil
+	case len(keys) == 1 && dialect.Supports(FeatureLastInsertId):
+		return model.insert(ctx, syntax)
+	case dialect.Supports(FeatureReturning):
+		return model.insertReturning(ctx, syntax)
+	}
+
+	return nil, &zModelErr{err:errors.New("the inserted key can not be returned by " + dialect.Name())}
+}
+
+// insertReturning inserts the row with RETURNING the primary key,
+// nil is given if no row is inserted
+func (model *zModel) insertReturning(ctx context.Context, syntax *zInsert) (key interface{}, err *zModelErr) {
+	var keys = primaryKeys(model.table)
+	if len(keys) == 0 {
+		return nil, &zModelErr{err:errors.New("no primary key defined")}
+	}
+	syntax.returning = keys
+
+	query,args,serr := syntax.query()
+	if serr != nil {
+		return nil, &zModelErr{query:query, args:args, err:serr}
+	}
+
+	var row = &zRow{columns: keys, value: make([]interface{}, len(keys))}
+	for i,column := range keys {
+		row.value[i] = model.columnTemplate(column)
+	}
+
+	sqlRow,err := model.queryRow(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if serr = row.fill(sqlRow); serr != nil {
+		return nil, &zModelErr{query:query, args:args, err:serr}
+	}
+	if row.filledMap == nil {
+		return nil, nil
+	}
+
+	var values = make([]interface{}, 0, len(keys))
+	for _,column := range keys {
+		v,_ := row.Get(column)
+		values = append(values, v)
+	}
+
+	if len(keys) == 1 {
+		return values[0], nil
+	}
+	return values, nil
+}
+
+// InsertMany inserts the rows with multiple rows insert statements,
+// the rows are split into chunks to stay under the batch size and the
+// placeholder limit of the dialect. Every row must assign the same columns.
+// id is the LastInsertId of the first chunk, which is the id of the first
+// row on mysql, id is 0 on a dialect without LastInsertId such as postgres.
+// Chunks are not atomic, run it in a transaction if needed.
+func (model *zModel) InsertMany(list []*AssignList) (id int64, rowsAffected int64, err *zModelErr) {
+	return model.InsertManyContext(context.Background(), list)
+}
+
+func (model *zModel) InsertManyContext(ctx context.Context, list []*AssignList) (id int64, rowsAffected int64, err *zModelErr) {
+	if list == nil || len(list) == 0 {
+		return 0, 0, nil
+	}
+
+	var rows = make([]AssignList, 0, len(list))
+	for _,row := range list {
+		if row == nil {
+			return 0, 0, &zModelErr{err:errors.New("nil row in insert list")}
+		}
+		rows = append(rows, *row)
+	}
+	if serr := model.checkStrictColumns(assignColumns(rows[0])...); serr != nil {
+		return 0, 0, &zModelErr{err:serr}
+	}
+
+	var chunk = model.batchSize
+	if chunk <= 0 {
+		chunk = zDefaultBatchSize
+	}
+	if columns := len(rows[0]); columns > 0 {
+		if max := useDialect(model.dialect).MaxPlaceholders() / columns; max < chunk {
+			chunk = max
+		}
+	}
+	if chunk <= 0 {
+		chunk = 1
+	}
+
+	for start := 0; start < len(rows); start += chunk {
+		end := start + chunk
+		if end > len(rows) {
+			end = len(rows)
+		}
+
+		var syntax = new(zMInsert)
+		syntax.table = model.table
+		syntax.dialect = model.dialect
+		syntax.rows = rows[start:end]
+
+		query,args,serr := syntax.query()
+		if serr != nil {
+			return id, rowsAffected, &zModelErr{query:query, args:args, err:serr}
+		}
+
+		result,err := model.exec(ctx, query, args...)
+		if err != nil {
+			return id, rowsAffected, err
+		}
+
+		if start == 0 && useDialect(model.dialect).Supports(FeatureLastInsertId) {
+			if id, serr = result.LastInsertId(); serr != nil {
+				return id, rowsAffected, &zModelErr{query:query, args:args, err:serr}
+			}
+		}
+
+		affected,serr := result.RowsAffected()
+		if serr != nil {
+			return id, rowsAffected, &zModelErr{query:query, args:args, err:serr}
+		}
+		rowsAffected += affected
+	}
+
+	return id, rowsAffected, nil
+}
+
+// InsertManyBind inserts a slice of structs (or struct pointers),
+// the columns are taken from the zcolumn tags. Every mapped column is
+// inserted, omitempty is ignored to keep the columns of the rows the same.
+// id is given as by InsertMany, 0 on a dialect without LastInsertId.
+func (model *zModel) InsertManyBind(objs interface{}) (id int64, rowsAffected int64, err *zModelErr) {
+	return model.InsertManyBindContext(context.Background(), objs)
+}
+
+func (model *zModel) InsertManyBindContext(ctx context.Context, objs interface{}) (id int64, rowsAffected int64, err *zModelErr) {
+	var r = reflect.ValueOf(objs)
+	if r.Kind() != reflect.Slice {
+		return 0, 0, &zModelErr{err:errors.New("you should bind a slice of struct")}
+	}
+
+	var list = make([]*AssignList, 0, r.Len())
+	for i := 0; i < r.Len(); i++ {
+		var obj = r.Index(i)
+		switch {
+		case obj.Kind() == reflect.Ptr && !obj.IsNil() && obj.Elem().Kind() == reflect.Struct:
+			list = append(list, AssignList{}.bind(obj.Interface(), true))
+		case obj.Kind() == reflect.Struct:
+			list = append(list, AssignList{}.bind(obj.Addr().Interface(), true))
+		default:
+			return 0, 0, &zModelErr{err:errors.Errorf("element %d is not a struct", i)}
+		}
+	}
+
+	return model.InsertManyContext(ctx, list)
+}
+
 func (model *zModel) Update(list *AssignList) (rowsAffected int64, err *zModelErr) {
+	return model.UpdateContext(context.Background(), list)
+}
+
+func (model *zModel) UpdateContext(ctx context.Context, list *AssignList) (rowsAffected int64, err *zModelErr) {
+	if serr := model.checkStrict(assignColumns(*list)...); serr != nil {
+		return 0, &zModelErr{err:serr}
+	}
+
 	var syntax = new(zUpdate)
 	syntax.table = model.table
+	syntax.dialect = model.dialect
 	syntax.assigns = *list
 
 	if model.query != nil {
@@ -236,9 +819,18 @@ func (model *zModel) Update(list *AssignList) (rowsAffected int64, err *zModelEr
 		syntax.where = new(zWhere).Where(model.table.SoftDelete().Column(), "=", model.table.SoftDelete().Value()).AndWhere(syntax.where)
 	}
 
-	if syntax.assigns != nil {
-		if id, ok := syntax.assigns[model.table.PrimaryKey()]; ok {
-			where := new(zWhere).Where(model.table.PrimaryKey(), "=", id)
+	// the row is matched by the primary key if all key columns are assigned
+	if keys := primaryKeys(model.table); syntax.assigns != nil && len(keys) > 0 {
+		var where = new(zWhere)
+		for _,key := range keys {
+			if id, ok := syntax.assigns[key]; ok {
+				where.Where(key, "=", id)
+			} else {
+				where = nil
+				break
+			}
+		}
+		if where != nil {
 			syntax.where = where.AndWhere(syntax.where)
 		}
 	}
@@ -248,7 +840,7 @@ func (model *zModel) Update(list *AssignList) (rowsAffected int64, err *zModelEr
 		return 0, &zModelErr{query:query, args:args, err:serr}
 	}
 
-	result,err := model.exec(query, args...)
+	result,err := model.exec(ctx, query, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -262,17 +854,49 @@ func (model *zModel) Update(list *AssignList) (rowsAffected int64, err *zModelEr
 }
 
 func (model *zModel) Delete() (rowsAffected int64, err *zModelErr) {
+	return model.DeleteContext(context.Background())
+}
+
+// DeleteByKey deletes the row with the primary key id as Delete does,
+// the conditions of the current query are not applied
+func (model *zModel) DeleteByKey(id interface{}) (rowsAffected int64, err *zModelErr) {
+	return model.DeleteByKeyContext(context.Background(), id)
+}
+
+func (model *zModel) DeleteByKeyContext(ctx context.Context, id interface{}) (rowsAffected int64, err *zModelErr) {
+	where,kerr := keyWhere(primaryKeys(model.table), id)
+	if kerr != nil {
+		return 0, &zModelErr{err:kerr}
+	}
+
+	var query = model.query
+	model.query = &zQueryBuilder{where: where}
+	defer func() { model.query = query }()
+
+	return model.DeleteContext(ctx)
+}
+
+func (model *zModel) DeleteContext(ctx context.Context) (rowsAffected int64, err *zModelErr) {
 	if model.table.SoftDelete() == nil {
-		return model.ForceDelete()
+		return model.ForceDeleteContext(ctx)
 	}
 
 	var softDelete = model.table.SoftDelete()
-	return model.Update(&AssignList{softDelete.Column(): softDelete.DeleteValue()})
+	return model.UpdateContext(ctx, &AssignList{softDelete.Column(): softDelete.DeleteValue()})
 }
 
 func (model *zModel) ForceDelete() (rowsAffected int64, err *zModelErr) {
+	return model.ForceDeleteContext(context.Background())
+}
+
+func (model *zModel) ForceDeleteContext(ctx context.Context) (rowsAffected int64, err *zModelErr) {
+	if serr := model.checkStrict(); serr != nil {
+		return 0, &zModelErr{err:serr}
+	}
+
 	var syntax = new(zDelete)
 	syntax.table = model.table
+	syntax.dialect = model.dialect
 
 	if model.query != nil {
 		syntax.where = model.query.where
@@ -289,7 +913,7 @@ func (model *zModel) ForceDelete() (rowsAffected int64, err *zModelErr) {
 		return 0, &zModelErr{query:query, args:args, err:serr}
 	}
 
-	result,err := model.exec(query, args...)
+	result,err := model.exec(ctx, query, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -302,26 +926,61 @@ func (model *zModel) ForceDelete() (rowsAffected int64, err *zModelErr) {
 	return rowsAffected,err
 }
 
+// Count gives the number of rows of the current query, a Distinct query
+// is counted by the columns of the table as selected by Get(nil)
 func (model *zModel) Count() (total int64, err *zModelErr) {
-	var syntax = new(zSelect)
-	syntax.table = model.table
-	if model.query != nil {
-		syntax.where = model.query.where
-	} else {
-		syntax.where = nil
-	}
+	return model.CountContext(context.Background())
+}
 
-	if model.table.SoftDelete() != nil {
-		syntax.where = new(zWhere).Where(model.table.SoftDelete().Column(), "=", model.table.SoftDelete().Value()).AndWhere(syntax.where)
+func (model *zModel) CountContext(ctx context.Context) (total int64, err *zModelErr) {
+	var syntax = model.selectSyntax()
+	syntax.orderby = nil
+	syntax.limit = nil
+
+	return model.count(ctx, syntax, nil)
+}
+
+// count gives the number of rows of the select syntax selecting column,
+// a grouped or distinct select is counted as a derived table
+func (model *zModel) count(ctx context.Context, countSyntax *zSelect, column *ZColumnList) (total int64, err *zModelErr) {
+	if serr := model.checkStrict(); serr != nil {
+		return 0, &zModelErr{err:serr}
 	}
 
+	var syntax = *countSyntax
+	syntax.lock = nil
+
 	var row = ZColumnList{"count(1) as total": int64(0)}.makeRow()
-	query,args,serr := syntax.query(row.columns...)
+	var query string
+	var args []interface{}
+	var serr error
+	switch {
+	case syntax.distinct:
+		// the distinct rows depend on the selected columns, the same
+		// columns as the rows query are selected in the derived table
+		if column == nil {
+			column = model.table.Columns()
+		}
+		var columns = column.makeRows().columns
+		if serr = model.checkStrictColumns(columns...); serr != nil {
+			return 0, &zModelErr{err:serr}
+		}
+		query,args,serr = syntax.query(columns...)
+	case syntax.groupby != nil && len(syntax.groupby.columns) > 0:
+		syntax.exprs = nil
+		query,args,serr = syntax.query("1")
+	default:
+		syntax.exprs = nil
+		query,args,serr = syntax.query(row.columns...)
+	}
 	if serr != nil {
 		return 0, &zModelErr{query:query, args:args, err:serr}
 	}
+	if syntax.distinct || (syntax.groupby != nil && len(syntax.groupby.columns) > 0) {
+		query = "SELECT " + row.columns[0] + " FROM (" + query + ") AS zorm_count"
+	}
 
-	sqlRow,err := model.queryRow(query, args...)
+	sqlRow,err := model.queryRow(ctx, query, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -331,16 +990,54 @@ func (model *zModel) Count() (total int64, err *zModelErr) {
 	}
 
 	if v, ok := row.Get("total"); ok {
-		return v.(int64), nil
+		if total, ok = v.(int64); ok {
+			return total, nil
+		}
 	}
 
 	return 0, &zModelErr{query:query, args:args, err:errors.New("result parse error")}
 }
 
-func (model *zModel) exec(query string, args ...interface{}) (sql.Result, *zModelErr) {
-	defer model.logQuery(query, args...)
+// zExecutor is implemented by both *sql.DB and *sql.Tx
+type zExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
 
-	result,err := RawExec(model.connection, query, args...)
+// tx gives the open transaction of the connection the model is created by
+func (model *zModel) tx() *sql.Tx {
+	if model.owner == nil {
+		return nil
+	}
+
+	return model.owner.transaction
+}
+
+// executor gives the transaction if the model is bound to one,
+// otherwise the db connection
+func (model *zModel) executor() (zExecutor, error) {
+	if tx := model.tx(); tx != nil {
+		return tx, nil
+	}
+
+	if model.connection == nil {
+		return nil, errors.New("no db connection")
+	}
+
+	return model.connection, nil
+}
+
+func (model *zModel) exec(ctx context.Context, query string, args ...interface{}) (sql.Result, *zModelErr) {
+	query = rebind(model.dialect, query)
+	defer model.logQuery(ctx, query, args...)
+
+	executor,err := model.executor()
+	if err != nil {
+		return nil, &zModelErr{query:query, args:args, err:err}
+	}
+
+	result,err := executor.ExecContext(ctx, query, args...)
 	if err != nil {
 		return result,&zModelErr{query:query, args:args, err:err}
 	}
@@ -348,10 +1045,16 @@ func (model *zModel) exec(query string, args ...interface{}) (sql.Result, *zMode
 	return result,nil
 }
 
-func (model *zModel) queryRows(query string, args ...interface{}) (*sql.Rows, *zModelErr) {
-	defer model.logQuery(query, args...)
+func (model *zModel) queryRows(ctx context.Context, query string, args ...interface{}) (*sql.Rows, *zModelErr) {
+	query = rebind(model.dialect, query)
+	defer model.logQuery(ctx, query, args...)
+
+	executor,err := model.executor()
+	if err != nil {
+		return nil, &zModelErr{query:query, args:args, err:err}
+	}
 
-	rows,err := RawQuery(model.connection, query, args...)
+	rows,err := executor.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, &zModelErr{query:query, args:args, err: err}
 	}
@@ -359,19 +1062,26 @@ func (model *zModel) queryRows(query string, args ...interface{}) (*sql.Rows, *z
 	return rows,nil
 }
 
-func (model *zModel) queryRow(query string, args ...interface{}) (*sql.Row, *zModelErr) {
-	defer model.logQuery(query, args...)
+func (model *zModel) queryRow(ctx context.Context, query string, args ...interface{}) (*sql.Row, *zModelErr) {
+	query = rebind(model.dialect, query)
+	defer model.logQuery(ctx, query, args...)
 
-	row,err := RawQueryRow(model.connection, query, args...)
+	executor,err := model.executor()
 	if err != nil {
 		return nil, &zModelErr{query:query, args:args, err:err}
 	}
 
-	return row,nil
+	return executor.QueryRowContext(ctx, query, args...),nil
 }
 
-func (model *zModel) logQuery(query string, args ...interface{}) {
-	if model.sqlLogger != nil {
+func (model *zModel) logQuery(ctx context.Context, query string, args ...interface{}) {
+	if model.sqlLogger == nil {
+		return
+	}
+
+	if logger, ok := model.sqlLogger.(zSqlContextLogger); ok {
+		logger.LogSQLContext(ctx, query, args...)
+	} else {
 		model.sqlLogger.LogSQL(query, args...)
 	}
 }
@@ -397,4 +1107,11 @@ func (err *zModelErr) Args() []interface{} {
 type zSqlLogger interface {
 	// LogSQL logs the sql query and args
 	LogSQL(query string, args ...interface{})
-}
\ No newline at end of file
+}
+
+type zSqlContextLogger interface {
+	zSqlLogger
+
+	// LogSQLContext logs the sql query and args with the query context
+	LogSQLContext(ctx context.Context, query string, args ...interface{})
+}
@@ -0,0 +1,288 @@
+package zorm
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type zTestContextLogger struct {
+	keys 	[]interface{}
+}
+
+type zTestContextKey string
+
+func (logger *zTestContextLogger) LogSQL(query string, args ...interface{}) {
+	fmt.Println(query, args)
+}
+
+func (logger *zTestContextLogger) LogSQLContext(ctx context.Context, query string, args ...interface{}) {
+	logger.keys = append(logger.keys, ctx.Value(zTestContextKey("trace")))
+	fmt.Println(query, args)
+}
+
+func TestZModel_Context(t *testing.T) {
+	var connection = zTestConnection(t, "model_context")
+	var logger = new(zTestContextLogger)
+	var model = connection.NewModel(new(zTestTable1), logger)
+
+	ctx := context.WithValue(context.Background(), zTestContextKey("trace"), "trace-1")
+	if _, err := model.InsertContext(ctx, &AssignList{"c1": "hhhh"}); err != nil {
+		t.Error(err)
+	}
+
+	if len(logger.keys) != 1 || logger.keys[0] != "trace-1" {
+		t.Error("context should be passed to the logger")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := model.CountContext(ctx); err == nil {
+		t.Error("canceled context should fail the query")
+	} else {
+		fmt.Println(err)
+	}
+}
+
+func TestZModel_InsertMany(t *testing.T) {
+	var connection = zTestConnection(t, "model_insert_many")
+	var model = connection.NewModel(new(zTestTable1), nil).BatchSize(2)
+
+	var rows = []testColumnStruct{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	var list = make([]*AssignList, 0)
+	for _, row := range rows {
+		list = append(list, &AssignList{"name": row.Name})
+	}
+
+	id, rowsAffected, err := model.InsertMany(list)
+	if err != nil {
+		t.Error(err)
+	}
+
+	queries := zTestQueries("model_insert_many")
+	fmt.Println(id, rowsAffected, queries)
+	if len(queries) != 2 || queries[0] != "INSERT INTO `test1` (`name`) VALUES (?),(?)" {
+		t.Error("rows should be inserted in chunks")
+	}
+
+	connection.dialect = new(PostgresDialect)
+	id, rowsAffected, err = connection.NewModel(new(zTestTable1), nil).InsertMany(list)
+	if err != nil || id != 0 || rowsAffected != 1 {
+		t.Error("the first id should be 0 without LastInsertId", id, rowsAffected, err)
+	}
+}
+
+type testBindStruct struct {
+	Name 		string 		`zcolumn:"c1"`
+	Count 		int 		`zcolumn:"c2,omitempty"`
+}
+
+func TestZModel_InsertManyBind(t *testing.T) {
+	var connection = zTestConnection(t, "model_insert_many_bind")
+	var model = connection.NewModel(new(zTestTable1), nil)
+
+	if _, _, err := model.InsertManyBind([]testBindStruct{{"a", 1}, {"b", 0}}); err != nil {
+		t.Error(err)
+	}
+	if _, _, err := model.InsertManyBind([]*testBindStruct{{"a", 0}, {"b", 2}}); err != nil {
+		t.Error(err)
+	}
+
+	queries := zTestQueries("model_insert_many_bind")
+	fmt.Println(queries)
+	if len(queries) != 2 || queries[0] != "INSERT INTO `test1` (`c1`,`c2`) VALUES (?,?),(?,?)" || queries[1] != queries[0] {
+		t.Error("zero omitempty columns should be inserted with the rows")
+	}
+}
+
+type testIntoStruct struct {
+	Id 			int32 		`zcolumn:"id"`
+	Name 		string 		`zcolumn:"c1"`
+	Score 		*float64 	`zcolumn:"c4"`
+}
+
+func TestZModel_GetInto(t *testing.T) {
+	var connection = zTestConnection(t, "model_get_into")
+	zTestResults["model_get_into"] = [][]driver.Value{
+		{int64(1), []byte("a"), float64(1.5)},
+		{int64(2), []byte("b"), nil},
+	}
+
+	var model = connection.NewModel(new(zTestTable1), nil)
+	model.NewQuery().Where("c2", ">", 1).OrderBy("id", "ASC")
+
+	var objs []testIntoStruct
+	if err := model.GetInto(&objs); err != nil {
+		t.Error(err)
+	}
+	fmt.Println(objs, zTestQueries("model_get_into"))
+	if len(objs) != 2 || objs[1].Name != "b" || objs[0].Score == nil || objs[1].Score != nil {
+		t.Error("unexpected scan result")
+	}
+
+	var ptrs []*testIntoStruct
+	if err := model.GetInto(&ptrs); err != nil || len(ptrs) != 2 {
+		t.Error("scan into slice of pointers failed")
+	}
+
+	var obj testIntoStruct
+	found, err := model.FindInto(1, &obj)
+	if err != nil || !found || obj.Id != 1 {
+		t.Error("unexpected find result")
+	}
+
+	zTestResults["model_get_into"] = nil
+	if found, _ := model.FirstInto(&obj); found {
+		t.Error("no row should be found")
+	}
+}
+
+func TestZModel_Lock(t *testing.T) {
+	var connection = zTestConnection(t, "model_lock")
+	zTestResults["model_lock"] = [][]driver.Value{{int64(1)}}
+
+	var model = connection.NewModel(new(zTestTable1), nil)
+	model.NewQuery().Where("c2", "=", 0).Limit(10).LockForUpdate().SkipLocked()
+	if _, err := model.Get(nil); err == nil {
+		t.Error("locking outside a transaction should fail")
+	}
+
+	err := connection.Tx(func(tx *ZConnection) error {
+		var model = tx.NewModel(new(zTestTable1), nil)
+		model.NewQuery().Where("c2", "=", 0).Limit(10).LockForShare().NoWait()
+		if _, err := model.Get(&ZColumnList{"id": int64(0)}); err != nil {
+			return err
+		}
+		if _, err := model.Count(); err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = connection.Tx(func(tx *ZConnection) error {
+		var model = tx.NewModel(new(zTestTable1), nil)
+		model.NewQuery().Where("c2", "=", 0).SkipLocked()
+		_, err := model.Get(nil)
+		return err
+	})
+	if err == nil {
+		t.Error("SKIP LOCKED without a lock mode should fail")
+	}
+
+	model.NewQuery().Where("c2", "=", 0).LockForUpdate()
+	if _, err := model.First(&ZColumnList{"id": int64(0)}); err == nil {
+		t.Error("locking the first row outside a transaction should fail")
+	}
+
+	err = connection.Tx(func(tx *ZConnection) error {
+		var model = tx.NewModel(new(zTestTable1), nil)
+		model.NewQuery().Where("c2", "=", 0).LockForUpdate()
+		if _, err := model.First(&ZColumnList{"id": int64(0)}); err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	queries := zTestQueries("model_lock")
+	if last := queries[len(queries)-2]; last != "SELECT `id` FROM `test1` WHERE (`c2` = ?) LIMIT ? OFFSET ? FOR UPDATE" {
+		t.Error("unexpected first row locking query: " + last)
+	}
+	if queries[1] != "SELECT `id` FROM `test1` WHERE (`c2` = ?) LIMIT ? OFFSET ? FOR SHARE NOWAIT" {
+		t.Error("unexpected locking query: " + queries[1])
+	}
+	if queries[2] != "SELECT count(1) as total FROM `test1` WHERE (`c2` = ?)" {
+		t.Error("count should not lock: " + queries[2])
+	}
+}
+
+func TestZModel_FindManyComposite(t *testing.T) {
+	var connection = zTestConnection(t, "model_find_composite")
+
+	var model = connection.NewModel(new(zTestTable3), nil)
+	if _, err := model.FindMany([][]interface{}{{int64(1), "a"}, {int64(1), "b"}}, &ZColumnList{"c1": ""}); err != nil {
+		t.Error(err)
+	}
+	if _, err := model.FindMany([]interface{}{int64(1)}, nil); err == nil {
+		t.Error("a composite key should have a value for every column")
+	}
+
+	connection.NewModel(new(zTestTable1), nil).FindMany([]int64{1, 2}, &ZColumnList{"c1": ""})
+
+	queries := zTestQueries("model_find_composite")
+	fmt.Println(queries)
+	if queries[0] != "SELECT `c1` FROM `test3` WHERE ((`tenant_id`,`id`) IN ((?,?),(?,?))) ORDER BY `tenant_id` ASC,`id` ASC LIMIT ? OFFSET ?" {
+		t.Error("unexpected composite key query: " + queries[0])
+	}
+	if queries[1] != "SELECT `c1` FROM `test1` WHERE (`id` IN (?,?)) ORDER BY `id` ASC LIMIT ? OFFSET ?" {
+		t.Error("unexpected key query: " + queries[1])
+	}
+}
+
+func TestZModel_CompositeKey(t *testing.T) {
+	var connection = zTestConnection(t, "model_composite_key")
+	var model = connection.NewModel(new(zTestTable3), nil)
+
+	model.Find([]interface{}{int64(1), "a"}, &ZColumnList{"c1": ""})
+	if _, err := model.Find(int64(1), nil); err == nil {
+		t.Error("a composite key should be a slice")
+	}
+
+	model.Update(&AssignList{"tenant_id": 1, "id": "a", "c1": "b"})
+	model.DeleteByKey([2]interface{}{1, "a"})
+
+	key, err := model.InsertKey(&AssignList{"tenant_id": 1, "id": "b", "c1": "c"})
+	if err != nil || len(key.([]interface{})) != 2 {
+		t.Error("the assigned key should be returned", key, err)
+	}
+
+	queries := zTestQueries("model_composite_key")
+	fmt.Println(queries)
+	if queries[0] != "SELECT `c1` FROM `test3` WHERE (`tenant_id` = ?) AND (`id` = ?) LIMIT ? OFFSET ?" {
+		t.Error("unexpected find query: " + queries[0])
+	}
+	if !strings.HasSuffix(queries[1], " WHERE (`tenant_id` = ?) AND (`id` = ?)") {
+		t.Error("unexpected update query: " + queries[1])
+	}
+	if queries[2] != "DELETE FROM `test3` WHERE (`tenant_id` = ?) AND (`id` = ?)" {
+		t.Error("unexpected delete query: " + queries[2])
+	}
+}
+
+func TestZModel_InsertReturning(t *testing.T) {
+	var connection = zTestConnection(t, "model_insert_returning")
+	connection.dialect = new(PostgresDialect)
+	zTestResults["model_insert_returning"] = [][]driver.Value{{int64(7)}}
+
+	id, err := connection.NewModel(new(zTestTable1), nil).Insert(&AssignList{"c1": "a"})
+	if err != nil || id != 7 {
+		t.Error("the insert id should be returned", id, err)
+	}
+
+	zTestResults["model_insert_returning"] = [][]driver.Value{{int64(2), []byte("uuid-1")}}
+	key, kerr := connection.NewModel(new(zTestTable3), nil).InsertKey(&AssignList{"c1": "a"})
+	if kerr != nil || key.([]interface{})[1] != "uuid-1" {
+		t.Error("the inserted key should be returned", key, kerr)
+	}
+
+	id, err = connection.NewModel(new(zTestTable3), nil).Insert(&AssignList{"tenant_id": 1, "id": "a"})
+	if err != nil || id != 0 {
+		t.Error("a composite key should give 0 without LastInsertId", id, err)
+	}
+
+	queries := zTestQueries("model_insert_returning")
+	fmt.Println(queries)
+	if queries[0] != `INSERT INTO "test1" ("c1") VALUES ($1) RETURNING "id"` || queries[1] != `INSERT INTO "test3" ("c1") VALUES ($1) RETURNING "tenant_id","id"` {
+		t.Error("unexpected returning query: " + queries[0])
+	}
+	if queries[2] != `INSERT INTO "test3" ("id","tenant_id") VALUES ($1,$2)` {
+		t.Error("unexpected composite key insert: " + queries[2])
+	}
+}
@@ -0,0 +1,392 @@
+package zorm
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	cursorSecretMu 	sync.RWMutex
+	cursorSecret 	[]byte
+)
+
+func init() {
+	cursorSecret = make([]byte, 32)
+	// a zero key would let anyone forge the tokens
+	if _, err := rand.Read(cursorSecret); err != nil {
+		panic(errors.Wrap(err, "zorm: can not generate the cursor secret"))
+	}
+}
+
+// SetCursorSecret sets the key signing the cursor tokens of CursorPaginate.
+// A random key is used by default, so the tokens are only valid within
+// the process; set a shared key when running more than one instance.
+func SetCursorSecret(secret []byte) {
+	cursorSecretMu.Lock()
+	defer cursorSecretMu.Unlock()
+	cursorSecret = append([]byte(nil), secret...)
+}
+
+// ZPage is a page of Paginate
+type ZPage struct {
+	Rows 		*zRows
+	Total 		int64
+	PerPage 	int64
+	CurrentPage int64
+	PageCount 	int64
+	HasMore 	bool
+	HasPrev 	bool
+}
+
+// Paginate counts the rows of the current query and gives the rows of
+// the page (1-based), both queries are built from the same query state.
+// The limit of the current query is replaced by the page.
+func (model *zModel) Paginate(page, perPage int64, column *ZColumnList) (*ZPage, *zModelErr) {
+	return model.PaginateContext(context.Background(), page, perPage, column)
+}
+
+func (model *zModel) PaginateContext(ctx context.Context, page, perPage int64, column *ZColumnList) (*ZPage, *zModelErr) {
+	if perPage <= 0 {
+		return nil, &zModelErr{err:errors.New("page size should be positive")}
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	var syntax = model.selectSyntax()
+	var orderby = syntax.orderby
+	syntax.orderby = nil
+	syntax.limit = nil
+
+	total, err := model.count(ctx, syntax, column)
+	if err != nil {
+		return nil, err
+	}
+
+	var result = &ZPage{
+		Total: total,
+		PerPage: perPage,
+		CurrentPage: page,
+		PageCount: (total + perPage - 1) / perPage,
+	}
+	result.HasMore = page < result.PageCount
+	result.HasPrev = page > 1
+
+	if (page-1)*perPage >= total {
+		if column == nil {
+			column = model.table.Columns()
+		}
+		result.Rows = column.makeRows()
+		result.Rows.rows = make([]zRow, 0)
+		return result, nil
+	}
+
+	syntax.orderby = orderby
+	syntax.limit = new(zLimit).Limit(perPage).Offset((page-1)*perPage)
+	if result.Rows, err = model.selectRows(ctx, syntax, column); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ZCursorPage is a page of CursorPaginate, NextCursor and PrevCursor
+// are empty if there is no next or previous page
+type ZCursorPage struct {
+	Rows 		*zRows
+	NextCursor 	string
+	PrevCursor 	string
+}
+
+func (page *ZCursorPage) HasNext() bool {
+	return page.NextCursor != ""
+}
+
+func (page *ZCursorPage) HasPrev() bool {
+	return page.PrevCursor != ""
+}
+
+// CursorPaginate gives a page of perPage rows after (or before) the cursor
+// by comparing the order by columns of the current query with the values
+// of the cursor row instead of using OFFSET. The primary key is appended to
+// the order by columns to make the order stable. An empty cursor gives the
+// first page. The order by columns should not be NULL.
+func (model *zModel) CursorPaginate(perPage int64, cursor string, column *ZColumnList) (*ZCursorPage, *zModelErr) {
+	return model.CursorPaginateContext(context.Background(), perPage, cursor, column)
+}
+
+func (model *zModel) CursorPaginateContext(ctx context.Context, perPage int64, cursor string, column *ZColumnList) (*ZCursorPage, *zModelErr) {
+	if perPage <= 0 {
+		return nil, &zModelErr{err:errors.New("page size should be positive")}
+	}
+
+	var orders = make([]zOrderItem, 0)
+	if model.query != nil && model.query.orderBy != nil {
+		orders = model.query.orderBy.items()
+	}
+	// the primary key columns make the order unique
+	for _,key := range primaryKeys(model.table) {
+		var ordered = false
+		for _,order := range orders {
+			if order.column == key {
+				ordered = true
+			}
+		}
+		if !ordered {
+			orders = append(orders, zOrderItem{column: key})
+		}
+	}
+	if len(orders) == 0 {
+		return nil, &zModelErr{err:errors.New("no order by column to paginate")}
+	}
+
+	var backward = false
+	var syntax = model.selectSyntax()
+	if cursor != "" {
+		token, err := decodeCursor(cursor, orders)
+		if err != nil {
+			return nil, &zModelErr{err:err}
+		}
+
+		backward = token.Prev
+		syntax.where = keysetWhere(orders, token.values, backward).AndWhere(syntax.where)
+	}
+
+	syntax.orderby = new(zOrderBy)
+	for _,order := range orders {
+		if order.desc != backward {
+			syntax.orderby.OrderBy(order.column, "DESC")
+		} else {
+			syntax.orderby.OrderBy(order.column, "ASC")
+		}
+	}
+	syntax.limit = new(zLimit).Limit(perPage + 1)
+
+	if column == nil {
+		column = model.table.Columns()
+	}
+	var columns = ZColumnList{}
+	for k,v := range *column {
+		columns[k] = v
+	}
+	for _,order := range orders {
+		if _,ok := columns[order.column]; !ok {
+			columns[order.column] = nil
+		}
+	}
+
+	rows, err := model.selectRows(ctx, syntax, &columns)
+	if err != nil {
+		return nil, err
+	}
+
+	var hasMore = int64(len(rows.rows)) > perPage
+	if hasMore {
+		rows.rows = rows.rows[:perPage]
+	}
+	if backward {
+		for i, j := 0, len(rows.rows)-1; i < j; i, j = i+1, j-1 {
+			rows.rows[i], rows.rows[j] = rows.rows[j], rows.rows[i]
+		}
+	}
+
+	var page = &ZCursorPage{Rows: rows}
+	if len(rows.rows) == 0 {
+		return page, nil
+	}
+
+	var serr error
+	if hasMore || backward {
+		if page.NextCursor, serr = encodeCursor(&rows.rows[len(rows.rows)-1], orders, false); serr != nil {
+			return nil, &zModelErr{err:serr}
+		}
+	}
+	if (hasMore && backward) || (cursor != "" && !backward) {
+		if page.PrevCursor, serr = encodeCursor(&rows.rows[0], orders, true); serr != nil {
+			return nil, &zModelErr{err:serr}
+		}
+	}
+
+	return page, nil
+}
+
+// keysetWhere renders the tuple comparison of the order by columns:
+// (c1 > v1) OR (c1 = v1 AND c2 > v2) OR ..., the comparison is reversed
+// for DESC columns and for the previous page
+func keysetWhere(orders []zOrderItem, values []interface{}, backward bool) (*zWhere) {
+	var where = new(zWhere)
+	for i := range orders {
+		var cond = new(zWhere)
+		for j := 0; j < i; j++ {
+			cond.Where(orders[j].column, "=", values[j])
+		}
+
+		if orders[i].desc != backward {
+			cond.Where(orders[i].column, "<", values[i])
+		} else {
+			cond.Where(orders[i].column, ">", values[i])
+		}
+
+		if i == 0 {
+			where.AndWhere(cond)
+		} else {
+			where.OrWhere(cond)
+		}
+	}
+
+	return new(zWhere).AndWhere(where)
+}
+
+// zCursorToken is the signed payload of the cursor token
+type zCursorToken struct {
+	Prev 		bool 		`json:"p,omitempty"`
+	Columns 	string 		`json:"c"`
+	Values 		[][2]string `json:"v"`
+
+	values 		[]interface{}
+}
+
+func orderColumns(orders []zOrderItem) string {
+	var columns = make([]string, len(orders))
+	for i, order := range orders {
+		columns[i] = order.column
+		if order.desc {
+			columns[i] = columns[i] + " DESC"
+		}
+	}
+	return strings.Join(columns, ",")
+}
+
+func encodeCursor(row *zRow, orders []zOrderItem, prev bool) (string, error) {
+	var token = zCursorToken{Prev: prev, Columns: orderColumns(orders)}
+	for _,order := range orders {
+		v, _ := row.Get(order.column)
+		value, err := encodeCursorValue(v)
+		if err != nil {
+			return "", errors.Wrapf(err, "cursor column %s", order.column)
+		}
+		token.Values = append(token.Values, value)
+	}
+
+	payload, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(signCursor(payload)), nil
+}
+
+func decodeCursor(cursor string, orders []zOrderItem) (*zCursorToken, error) {
+	var parts = strings.Split(cursor, ".")
+	if len(parts) != 2 {
+		return nil, errors.New("invalid cursor")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, signCursor(payload)) {
+		return nil, errors.New("invalid cursor signature")
+	}
+
+	var token = new(zCursorToken)
+	if err := json.Unmarshal(payload, token); err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+	if token.Columns != orderColumns(orders) || len(token.Values) != len(orders) {
+		return nil, errors.New("cursor does not match the order by columns")
+	}
+
+	for _,value := range token.Values {
+		v, err := decodeCursorValue(value)
+		if err != nil {
+			return nil, err
+		}
+		token.values = append(token.values, v)
+	}
+
+	return token, nil
+}
+
+func signCursor(payload []byte) []byte {
+	cursorSecretMu.RLock()
+	defer cursorSecretMu.RUnlock()
+
+	var mac = hmac.New(sha256.New, cursorSecret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// encodeCursorValue encodes the value with its type so that
+// it is decoded as the same type
+func encodeCursorValue(v interface{}) ([2]string, error) {
+	switch v := v.(type) {
+	case int:
+		return [2]string{"i", strconv.FormatInt(int64(v), 10)}, nil
+	case int8:
+		return [2]string{"i", strconv.FormatInt(int64(v), 10)}, nil
+	case int16:
+		return [2]string{"i", strconv.FormatInt(int64(v), 10)}, nil
+	case int32:
+		return [2]string{"i", strconv.FormatInt(int64(v), 10)}, nil
+	case int64:
+		return [2]string{"i", strconv.FormatInt(v, 10)}, nil
+	case uint:
+		return [2]string{"u", strconv.FormatUint(uint64(v), 10)}, nil
+	case uint8:
+		return [2]string{"u", strconv.FormatUint(uint64(v), 10)}, nil
+	case uint16:
+		return [2]string{"u", strconv.FormatUint(uint64(v), 10)}, nil
+	case uint32:
+		return [2]string{"u", strconv.FormatUint(uint64(v), 10)}, nil
+	case uint64:
+		return [2]string{"u", strconv.FormatUint(v, 10)}, nil
+	case float32:
+		return [2]string{"f", strconv.FormatFloat(float64(v), 'g', -1, 64)}, nil
+	case float64:
+		return [2]string{"f", strconv.FormatFloat(v, 'g', -1, 64)}, nil
+	case bool:
+		return [2]string{"o", strconv.FormatBool(v)}, nil
+	case string:
+		return [2]string{"s", v}, nil
+	case []byte:
+		return [2]string{"s", string(v)}, nil
+	case time.Time:
+		return [2]string{"t", v.Format(time.RFC3339Nano)}, nil
+	case nil:
+		return [2]string{}, errors.New("NULL value can not be used in cursor")
+	}
+
+	return [2]string{}, errors.Errorf("unsupported cursor value type %T", v)
+}
+
+func decodeCursorValue(value [2]string) (interface{}, error) {
+	switch value[0] {
+	case "i":
+		return strconv.ParseInt(value[1], 10, 64)
+	case "u":
+		return strconv.ParseUint(value[1], 10, 64)
+	case "f":
+		return strconv.ParseFloat(value[1], 64)
+	case "o":
+		return strconv.ParseBool(value[1])
+	case "s":
+		return value[1], nil
+	case "t":
+		return time.Parse(time.RFC3339Nano, value[1])
+	}
+
+	return nil, errors.New("invalid cursor value")
+}
@@ -0,0 +1,80 @@
+package zorm
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"testing"
+)
+
+func TestZModel_CursorPaginate(t *testing.T) {
+	var connection = zTestConnection(t, "model_cursor_paginate")
+	zTestResults["model_cursor_paginate"] = [][]driver.Value{{int64(3)}, {int64(2)}, {int64(1)}}
+
+	var model = connection.NewModel(new(zTestTable1), nil)
+	model.NewQuery().Where("c1", "=", "a").OrderBy("id", "DESC")
+
+	page, err := model.CursorPaginate(2, "", &ZColumnList{"id": int64(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println(page.Rows.Count(), page.NextCursor, page.PrevCursor)
+	if page.Rows.Count() != 2 || !page.HasNext() || page.HasPrev() {
+		t.Error("unexpected first page")
+	}
+
+	zTestResults["model_cursor_paginate"] = [][]driver.Value{{int64(1)}}
+	next, err := model.CursorPaginate(2, page.NextCursor, &ZColumnList{"id": int64(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Rows.Count() != 1 || next.HasNext() || !next.HasPrev() {
+		t.Error("unexpected next page")
+	}
+
+	queries := zTestQueries("model_cursor_paginate")
+	fmt.Println(queries)
+	if queries[1] != "SELECT `id` FROM `test1` WHERE (((`id` < ?))) AND ((`c1` = ?)) ORDER BY `id` DESC LIMIT ? OFFSET ?" {
+		t.Error("unexpected cursor query: " + queries[1])
+	}
+
+	if _, err := model.CursorPaginate(2, page.NextCursor + "x", nil); err == nil {
+		t.Error("tampered cursor should fail")
+	}
+
+	model.NewQuery().OrderBy("c1", "ASC")
+	if _, err := model.CursorPaginate(2, page.NextCursor, nil); err == nil {
+		t.Error("cursor of other order should fail")
+	}
+}
+
+func TestKeysetWhere(t *testing.T) {
+	var orders = []zOrderItem{{column: "c1"}, {column: "c2", desc: true}, {column: "id"}}
+
+	fmt.Println(keysetWhere(orders, []interface{}{"a", 10, 1}, false).build(nil))
+	fmt.Println(keysetWhere(orders, []interface{}{"a", 10, 1}, true).build(nil))
+}
+
+func TestZModel_Paginate(t *testing.T) {
+	var connection = zTestConnection(t, "model_paginate")
+	zTestResults["model_paginate"] = [][]driver.Value{{int64(5)}}
+
+	var model = connection.NewModel(new(zTestTable1), nil)
+	model.NewQuery().Where("c1", "=", "a").GroupBy("c2").OrderBy("c2", "ASC")
+
+	page, err := model.Paginate(2, 2, &ZColumnList{"c2": int16(0)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queries := zTestQueries("model_paginate")
+	fmt.Println(page.Total, page.PageCount, page.HasMore, page.HasPrev, queries)
+	if page.Total != 5 || page.PageCount != 3 || !page.HasMore || !page.HasPrev {
+		t.Error("unexpected page")
+	}
+	if queries[0] != "SELECT count(1) as total FROM (SELECT 1 FROM `test1` WHERE (`c1` = ?) GROUP BY `c2`) AS zorm_count" {
+		t.Error("unexpected count query: " + queries[0])
+	}
+	if queries[1] != "SELECT `c2` FROM `test1` WHERE (`c1` = ?) GROUP BY `c2` ORDER BY `c2` ASC LIMIT ? OFFSET ?" {
+		t.Error("unexpected page query: " + queries[1])
+	}
+}
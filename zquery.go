@@ -1,6 +1,7 @@
 package zorm
 
 import (
+	"context"
 	"database/sql"
 	"github.com/pkg/errors"
 	_ "github.com/go-sql-driver/mysql"
@@ -14,10 +15,35 @@ func WhereBetween(column string, v1, v2 interface{}) (*zWhere) {
 	return new(zWhere).Between(column, v1, v2)
 }
 
+func WhereNotBetween(column string, v1, v2 interface{}) (*zWhere) {
+	return new(zWhere).NotBetween(column, v1, v2)
+}
+
+// WhereColumns compares two columns, WhereColumn compares a column with a value
+func WhereColumns(column, operation, other string) (*zWhere) {
+	return new(zWhere).CompareColumns(column, operation, other)
+}
+
+func WhereNot(where *zWhere) (*zWhere) {
+	return new(zWhere).Not(where)
+}
+
+func WhereTupleIn(columns []string, values ...[]interface{}) (*zWhere) {
+	return new(zWhere).TupleIn(columns, values...)
+}
+
+func WhereTuple(columns []string, operation string, values []interface{}) (*zWhere) {
+	return new(zWhere).TupleWhere(columns, operation, values)
+}
+
 func WhereIn(column string, value ...interface{}) (*zWhere) {
 	return new(zWhere).In(column, value...)
 }
 
+func WhereNotIn(column string, value ...interface{}) (*zWhere) {
+	return new(zWhere).NotIn(column, value...)
+}
+
 func WhereRaw(query string, args ...interface{}) (*zWhere) {
 	return new(zWhere).Raw(query, args...)
 }
@@ -26,6 +52,62 @@ func WhereLike(column, pattern string) (*zWhere) {
 	return new(zWhere).Like(column, pattern)
 }
 
+func WhereNotLike(column, pattern string) (*zWhere) {
+	return new(zWhere).NotLike(column, pattern)
+}
+
+func WhereILike(column, pattern string) (*zWhere) {
+	return new(zWhere).ILike(column, pattern)
+}
+
+func WhereNotILike(column, pattern string) (*zWhere) {
+	return new(zWhere).NotILike(column, pattern)
+}
+
+func WhereContains(column, value string) (*zWhere) {
+	return new(zWhere).Contains(column, value)
+}
+
+func WhereStartsWith(column, value string) (*zWhere) {
+	return new(zWhere).StartsWith(column, value)
+}
+
+func WhereEndsWith(column, value string) (*zWhere) {
+	return new(zWhere).EndsWith(column, value)
+}
+
+func WhereIContains(column, value string) (*zWhere) {
+	return new(zWhere).IContains(column, value)
+}
+
+func WhereIStartsWith(column, value string) (*zWhere) {
+	return new(zWhere).IStartsWith(column, value)
+}
+
+func WhereIEndsWith(column, value string) (*zWhere) {
+	return new(zWhere).IEndsWith(column, value)
+}
+
+func WhereInSub(column string, sub *zSubQuery) (*zWhere) {
+	return new(zWhere).InSub(column, sub)
+}
+
+func WhereExists(sub *zSubQuery) (*zWhere) {
+	return new(zWhere).Exists(sub)
+}
+
+func WhereNotExists(sub *zSubQuery) (*zWhere) {
+	return new(zWhere).NotExists(sub)
+}
+
+func WhereNull(column string) (*zWhere) {
+	return new(zWhere).Null(column)
+}
+
+func WhereNotNull(column string) (*zWhere) {
+	return new(zWhere).NotNull(column)
+}
+
 func JoinOn(where *zWhere) (*zJoinOn) {
 	var joinOn = new(zJoinOn)
 	joinOn.where = where
@@ -62,12 +144,39 @@ func RawQueryRow(connection *sql.DB, query string, args ...interface{}) (*sql.Ro
 	return connection.QueryRow(query, args...), nil
 }
 
+func RawExecContext(ctx context.Context, connection *sql.DB, query string, args ...interface{}) (sql.Result, error) {
+	if connection == nil {
+		return nil, errors.New("no db connection")
+	}
+
+	return connection.ExecContext(ctx, query, args...)
+}
+
+func RawQueryContext(ctx context.Context, connection *sql.DB, query string, args ...interface{}) (*sql.Rows, error) {
+	if connection == nil {
+		return nil, errors.New("no db connection")
+	}
+
+	return connection.QueryContext(ctx, query, args...)
+}
+
+func RawQueryRowContext(ctx context.Context, connection *sql.DB, query string, args ...interface{}) (*sql.Row, error) {
+	if connection == nil {
+		return nil, errors.New("no db connection")
+	}
+
+	return connection.QueryRowContext(ctx, query, args...), nil
+}
+
 // zquery
 type zQueryBuilder struct {
 	where 		*zWhere
 	orderBy 	*zOrderBy
 	groupBy 	*zGroupBy
 	limit 		*zLimit
+	columns 	[]zColumnExpr
+	distinct 	bool
+	lock 		*zLock
 }
 
 func (query *zQueryBuilder) Where(column, operation string, value interface{}) (*zQueryBuilder) {
@@ -95,6 +204,78 @@ func (query *zQueryBuilder) WhereLike(column, pattern string) (*zQueryBuilder) {
 	return query
 }
 
+func (query *zQueryBuilder) WhereNotLike(column, pattern string) (*zQueryBuilder) {
+	if query.where == nil {
+		query.where = new(zWhere)
+	}
+	query.where.NotLike(column, pattern)
+	return query
+}
+
+func (query *zQueryBuilder) WhereILike(column, pattern string) (*zQueryBuilder) {
+	if query.where == nil {
+		query.where = new(zWhere)
+	}
+	query.where.ILike(column, pattern)
+	return query
+}
+
+func (query *zQueryBuilder) WhereNotILike(column, pattern string) (*zQueryBuilder) {
+	if query.where == nil {
+		query.where = new(zWhere)
+	}
+	query.where.NotILike(column, pattern)
+	return query
+}
+
+func (query *zQueryBuilder) WhereContains(column, value string) (*zQueryBuilder) {
+	if query.where == nil {
+		query.where = new(zWhere)
+	}
+	query.where.Contains(column, value)
+	return query
+}
+
+func (query *zQueryBuilder) WhereStartsWith(column, value string) (*zQueryBuilder) {
+	if query.where == nil {
+		query.where = new(zWhere)
+	}
+	query.where.StartsWith(column, value)
+	return query
+}
+
+func (query *zQueryBuilder) WhereEndsWith(column, value string) (*zQueryBuilder) {
+	if query.where == nil {
+		query.where = new(zWhere)
+	}
+	query.where.EndsWith(column, value)
+	return query
+}
+
+func (query *zQueryBuilder) WhereIContains(column, value string) (*zQueryBuilder) {
+	if query.where == nil {
+		query.where = new(zWhere)
+	}
+	query.where.IContains(column, value)
+	return query
+}
+
+func (query *zQueryBuilder) WhereIStartsWith(column, value string) (*zQueryBuilder) {
+	if query.where == nil {
+		query.where = new(zWhere)
+	}
+	query.where.IStartsWith(column, value)
+	return query
+}
+
+func (query *zQueryBuilder) WhereIEndsWith(column, value string) (*zQueryBuilder) {
+	if query.where == nil {
+		query.where = new(zWhere)
+	}
+	query.where.IEndsWith(column, value)
+	return query
+}
+
 func (query *zQueryBuilder) WhereIn(column string, value ...interface{}) (*zQueryBuilder) {
 	if query.where == nil {
 		query.where = new(zWhere)
@@ -111,6 +292,156 @@ func (query *zQueryBuilder) WhereBetween(column string, v1, v2 interface{}) (*zQ
 	return query
 }
 
+func (query *zQueryBuilder) WhereNotIn(column string, value ...interface{}) (*zQueryBuilder) {
+	if query.where == nil {
+		query.where = new(zWhere)
+	}
+	query.where.NotIn(column, value...)
+	return query
+}
+
+func (query *zQueryBuilder) WhereNotBetween(column string, v1, v2 interface{}) (*zQueryBuilder) {
+	if query.where == nil {
+		query.where = new(zWhere)
+	}
+	query.where.NotBetween(column, v1, v2)
+	return query
+}
+
+func (query *zQueryBuilder) WhereColumns(column, operation, other string) (*zQueryBuilder) {
+	if query.where == nil {
+		query.where = new(zWhere)
+	}
+	query.where.CompareColumns(column, operation, other)
+	return query
+}
+
+func (query *zQueryBuilder) WhereNot(where *zWhere) (*zQueryBuilder) {
+	if query.where == nil {
+		query.where = new(zWhere)
+	}
+	query.where.Not(where)
+	return query
+}
+
+func (query *zQueryBuilder) WhereTupleIn(columns []string, values ...[]interface{}) (*zQueryBuilder) {
+	if query.where == nil {
+		query.where = new(zWhere)
+	}
+	query.where.TupleIn(columns, values...)
+	return query
+}
+
+func (query *zQueryBuilder) WhereTuple(columns []string, operation string, values []interface{}) (*zQueryBuilder) {
+	if query.where == nil {
+		query.where = new(zWhere)
+	}
+	query.where.TupleWhere(columns, operation, values)
+	return query
+}
+
+func (query *zQueryBuilder) WhereInSub(column string, sub *zSubQuery) (*zQueryBuilder) {
+	if query.where == nil {
+		query.where = new(zWhere)
+	}
+	query.where.InSub(column, sub)
+	return query
+}
+
+func (query *zQueryBuilder) WhereExists(sub *zSubQuery) (*zQueryBuilder) {
+	if query.where == nil {
+		query.where = new(zWhere)
+	}
+	query.where.Exists(sub)
+	return query
+}
+
+func (query *zQueryBuilder) WhereNotExists(sub *zSubQuery) (*zQueryBuilder) {
+	if query.where == nil {
+		query.where = new(zWhere)
+	}
+	query.where.NotExists(sub)
+	return query
+}
+
+// SelectSub selects the scalar subquery as alias
+// in addition to the selected columns
+func (query *zQueryBuilder) SelectSub(sub *zSubQuery, alias string) (*zQueryBuilder) {
+	if sub == nil || alias == "" {
+		return query
+	}
+
+	query.columns = append(query.columns, zColumnExpr{expr: "(" + sub.query + ")", alias: alias, args: sub.args})
+	return query
+}
+
+// Select selects the expressions in addition to the selected columns,
+// the args of the expressions are bound before the where args
+func (query *zQueryBuilder) Select(expr ...zColumnExpr) (*zQueryBuilder) {
+	query.columns = append(query.columns, expr...)
+	return query
+}
+
+// Distinct selects distinct rows only
+func (query *zQueryBuilder) Distinct() (*zQueryBuilder) {
+	query.distinct = true
+	return query
+}
+
+// LockForUpdate locks the selected rows for update,
+// the model should be bound to a transaction
+func (query *zQueryBuilder) LockForUpdate() (*zQueryBuilder) {
+	return query.lockRows("UPDATE")
+}
+
+// LockForShare locks the selected rows for share,
+// the model should be bound to a transaction
+func (query *zQueryBuilder) LockForShare() (*zQueryBuilder) {
+	return query.lockRows("SHARE")
+}
+
+// NoWait fails the locking select at once if a row is locked
+func (query *zQueryBuilder) NoWait() (*zQueryBuilder) {
+	return query.lockOption("NOWAIT")
+}
+
+// SkipLocked skips the locked rows in the locking select
+func (query *zQueryBuilder) SkipLocked() (*zQueryBuilder) {
+	return query.lockOption("SKIP LOCKED")
+}
+
+func (query *zQueryBuilder) lockRows(mode string) (*zQueryBuilder) {
+	if query.lock == nil {
+		query.lock = new(zLock)
+	}
+	query.lock.mode = mode
+	return query
+}
+
+func (query *zQueryBuilder) lockOption(option string) (*zQueryBuilder) {
+	if query.lock == nil {
+		query.lock = new(zLock)
+	}
+	query.lock.option = option
+	return query
+}
+
+func (query *zQueryBuilder) WhereNull(column string) (*zQueryBuilder) {
+	if query.where == nil {
+		query.where = new(zWhere)
+	}
+	query.where.Null(column)
+	return query
+}
+
+func (query *zQueryBuilder) WhereNotNull(column string) (*zQueryBuilder) {
+	if query.where == nil {
+		query.where = new(zWhere)
+	}
+	query.where.NotNull(column)
+	return query
+}
+
 func (query *zQueryBuilder) WhereAnd(where *zWhere) (*zQueryBuilder) {
 	if query.where == nil {
 		query.where = where
@@ -0,0 +1,86 @@
+package zorm
+
+import (
+	"strings"
+)
+
+// zSubQuery is a select query used in WHERE (In, Exists, comparisons),
+// as a derived table in FROM or joins, or as a scalar column.
+// The query and args are taken when the subquery is created.
+type zSubQuery struct {
+	query 		string
+	args 		[]interface{}
+	alias 		string
+	columns 	[]string
+	primaryKey 	string
+}
+
+// SubQuery gives a subquery of the current query selecting column,
+// the columns of the table are selected if no column is given
+func (model *zModel) SubQuery(column ...string) (*zSubQuery, *zModelErr) {
+	var syntax = model.selectSyntax()
+
+	if len(column) == 0 {
+		column = model.table.Columns().makeRow().columns
+	}
+
+	if err := model.checkStrict(column...); err != nil {
+		return nil, &zModelErr{err:err}
+	}
+
+	query,args,err := syntax.query(column...)
+	if err != nil {
+		return nil, &zModelErr{query:query, args:args, err:err}
+	}
+
+	var columns = append([]string(nil), column...)
+	for _,expr := range syntax.exprs {
+		columns = append(columns, expr.column())
+	}
+
+	return &zSubQuery{query: query, args: args, columns: columns, primaryKey: model.table.PrimaryKey()}, nil
+}
+
+// As gives a copy of the subquery with the alias, used as a derived table
+func (sub *zSubQuery) As(alias string) (*zSubQuery) {
+	var aliased = *sub
+	aliased.alias = alias
+	return &aliased
+}
+
+func (sub *zSubQuery) Table() (name string) {
+	return sub.tableQuery(nil)
+}
+
+// tableQuery renders the derived table with the alias quoted by the dialect
+func (sub *zSubQuery) tableQuery(dialect Dialect) string {
+	if sub.alias == "" {
+		return "(" + sub.query + ")"
+	}
+
+	return "(" + sub.query + ") AS " + useDialect(dialect).Quote(sub.alias)
+}
+
+func (sub *zSubQuery) PrimaryKey() string {
+	return sub.primaryKey
+}
+
+// Columns gives the selected columns by their names in the derived table
+func (sub *zSubQuery) Columns() (*ZColumnList) {
+	var columns = ZColumnList{}
+	for _,column := range sub.columns {
+		if idxAs := strings.Index(strings.ToUpper(column), " AS "); idxAs > 0 {
+			column = column[idxAs+4:]
+		} else if idxDot := strings.LastIndex(column, "."); idxDot > 0 {
+			column = column[idxDot+1:]
+		}
+		columns[strings.TrimSpace(column)] = nil
+	}
+
+	return &columns
+}
+
+// SoftDelete is applied in the subquery already
+func (sub *zSubQuery) SoftDelete() SoftDelete {
+	return nil
+}
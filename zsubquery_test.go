@@ -0,0 +1,45 @@
+package zorm
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestZSubQuery(t *testing.T) {
+	var connection = zTestConnection(t, "model_subquery")
+
+	var inner = connection.NewModel(new(zTestTable2), nil)
+	inner.NewQuery().Where("c2", ">", 10)
+	sub, err := inner.SubQuery("id")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var where = WhereInSub("id", sub).Exists(sub).Where("c4", ">", sub)
+	query, args := where.build(nil)
+	fmt.Println(query, args)
+	if len(args) != 6 {
+		t.Error("subquery args should be merged")
+	}
+
+	var model = connection.NewModel(new(zTestTable1), nil)
+	model.NewQuery().SelectSub(sub, "sub_id").Where("c1", "=", "a")
+	model.Get(&ZColumnList{"id": int64(0)})
+
+	var derived = connection.NewModel(sub.As("t"), nil)
+	derived.NewQuery().Where("id", ">", 1)
+	derived.Get(nil)
+
+	var join = &ZJoinTable{TableReference: new(zTestTable1), Alias: "t1"}
+	join.Join(sub, "t2", JoinOn(WhereRaw("t1.id = t2.id")))
+	fmt.Println(join.Table(), join.tableArgs())
+
+	queries := zTestQueries("model_subquery")
+	fmt.Println(queries)
+	if queries[0] != "SELECT `id`,(SELECT `id` FROM `test2` WHERE (`delete_time` = ?) AND ((`c2` > ?))) AS `sub_id` FROM `test1` WHERE (`c1` = ?)" {
+		t.Error("unexpected select sub query: " + queries[0])
+	}
+	if queries[1] != "SELECT `id` FROM (SELECT `id` FROM `test2` WHERE (`delete_time` = ?) AND ((`c2` > ?))) AS `t` WHERE (`id` > ?)" {
+		t.Error("unexpected derived table query: " + queries[1])
+	}
+}
@@ -2,6 +2,7 @@ package zorm
 
 import (
 	"github.com/pkg/errors"
+	"sort"
 	"strings"
 )
 
@@ -24,6 +25,7 @@ type zDelete struct {
 	where 		*zWhere
 	orderBy 	*zOrderBy
 	limit 		*zLimit
+	dialect 	Dialect
 }
 
 func (delete *zDelete) syntax() string {
@@ -40,10 +42,15 @@ func (delete *zDelete) query() (query string, args []interface{}, err error) {
 		return "", nil, &SyntaxError{syntax:delete.syntax(), err:errors.New("join table is not supported")}
 	}
 
-	query = "DELETE FROM " + delete.table.Table()
+	var dialect = useDialect(delete.dialect)
+	query = "DELETE FROM " + tableName(dialect, delete.table)
 	args = make([]interface{}, 0)
 	if delete.where != nil {
-		wquery,wargs := delete.where.build()
+		if werr := delete.where.error(); werr != nil {
+			return "", nil, &SyntaxError{syntax:delete.syntax(), err:werr}
+		}
+
+		wquery,wargs := delete.where.build(dialect)
 		if wquery != "" {
 			query = query + " WHERE " + wquery
 
@@ -54,8 +61,16 @@ func (delete *zDelete) query() (query string, args []interface{}, err error) {
 	}
 
 	if delete.orderBy != nil {
-		oquery,oargs := delete.orderBy.build()
+		if oerr := delete.orderBy.error(); oerr != nil {
+			return "", nil, &SyntaxError{syntax:delete.syntax(), err:oerr}
+		}
+
+		oquery,oargs := delete.orderBy.build(dialect)
 		if oquery != "" {
+			if !dialect.Supports(FeatureDeleteLimit) {
+				return "", nil, &SyntaxError{syntax:delete.syntax(), err:errors.New("order by is not supported by " + dialect.Name())}
+			}
+
 			query = query + " ORDER BY " + oquery
 			if oargs != nil {
 				args = append(args, oargs...)
@@ -64,7 +79,11 @@ func (delete *zDelete) query() (query string, args []interface{}, err error) {
 	}
 
 	if delete.limit != nil {
-		lquery,largs := delete.limit.build()
+		if !dialect.Supports(FeatureDeleteLimit) {
+			return "", nil, &SyntaxError{syntax:delete.syntax(), err:errors.New("limit is not supported by " + dialect.Name())}
+		}
+
+		lquery,largs := dialect.Limit(delete.limit.rowCount, delete.limit.iOffset, false)
 		if lquery != "" {
 			query = query + " LIMIT " + lquery
 			if largs != nil {
@@ -80,6 +99,7 @@ func (delete *zDelete) query() (query string, args []interface{}, err error) {
 type zMDelete struct {
 	table		*ZJoinTable
 	where 		*zWhere
+	dialect 	Dialect
 }
 
 func (delete *zMDelete) syntax() string {
@@ -95,17 +115,28 @@ func (delete *zMDelete) query(tableDeleted ...string) (query string, args []inte
 		return "",nil, &SyntaxError{syntax:delete.syntax(), err:errors.New("no table deleted")}
 	}
 
-	query = "DELETE " + strings.Join(tableDeleted, ",") + " FROM " + delete.table.Table()
-	if args = tableArgs(delete.table); args == nil {
+	var dialect = useDialect(delete.dialect)
+	if !dialect.Supports(FeatureMultiDelete) {
+		return "",nil, &SyntaxError{syntax:delete.syntax(), err:errors.New("multiple table delete is not supported by " + dialect.Name())}
+	}
+
+	var tquery string
+	tquery,args = delete.table.build(dialect)
+	query = "DELETE " + strings.Join(quoteColumns(dialect, tableDeleted), ",") + " FROM " + tquery
+	if args == nil {
 		args = make([]interface{}, 0)
 	}
 	if delete.where != nil {
-		wquery,wargs := delete.where.build()
+		if werr := delete.where.error(); werr != nil {
+			return "", nil, &SyntaxError{syntax:delete.syntax(), err:werr}
+		}
+
+		wquery,wargs := delete.where.build(dialect)
 		if wquery != "" {
 			query = query + " WHERE " + wquery
 
 			if wargs != nil {
-				args = wargs
+				args = append(args, wargs...)
 			}
 		}
 	}
@@ -117,6 +148,20 @@ func (delete *zMDelete) query(tableDeleted ...string) (query string, args []inte
 type zInsert struct {
 	table 			ZTable
 	assigns 		AssignList
+	dialect 		Dialect
+
+	// INSERT IGNORE / ON CONFLICT DO NOTHING
+	ignore 			bool
+	// REPLACE INTO
+	replace 		bool
+	// columns updated if the row exists, upsert if not empty
+	updates 		[]string
+	// upsert keeping the existing row, a no-op update
+	keepExisting 	bool
+	// conflict target of ON CONFLICT, the primary key by default
+	conflict 		[]string
+	// RETURNING columns
+	returning 		[]string
 }
 
 func (insert *zInsert) syntax() string {
@@ -137,13 +182,176 @@ func (insert *zInsert) query() (query string, args []interface{}, err error) {
 		return "", nil, &SyntaxError{syntax:insert.syntax(), err:errors.New("join table is not supported")}
 	}
 
-	query = ""
-	args = make([]interface{}, 0)
-	for column,value := range insert.assigns {
-		query = query + column + "=?,"
-		args = append(args, value)
+	var dialect = useDialect(insert.dialect)
+	var verb = "INSERT INTO "
+	switch {
+	case insert.replace:
+		if len(insert.updates) > 0 || insert.ignore {
+			return "", nil, &SyntaxError{syntax:insert.syntax(), err:errors.New("replace can not be used with ignore or upsert")}
+		}
+
+		if !dialect.Supports(FeatureReplace) {
+			return "", nil, &SyntaxError{syntax:insert.syntax(), err:errors.New("replace is not supported by " + dialect.Name())}
+		}
+		verb = "REPLACE INTO "
+	case insert.ignore && len(insert.updates) == 0 && !dialect.Supports(FeatureOnConflict):
+		verb = "INSERT IGNORE INTO "
+	}
+
+	var columns = assignColumns(insert.assigns)
+	args = make([]interface{}, 0, len(columns))
+	for _,column := range columns {
+		args = append(args, insert.assigns[column])
+	}
+
+	var quoted = quoteIdentifiers(dialect, columns)
+	if dialect.Supports(FeatureInsertSet) {
+		query = verb + tableName(dialect, insert.table) + " SET " + strings.Join(quoted, "=?,") + "=?"
+	} else {
+		query = verb + tableName(dialect, insert.table) + " (" + strings.Join(quoted, ",") + ") VALUES (" +
+			strings.TrimRight(strings.Repeat("?,", len(columns)), ",") + ")"
+	}
+
+	conflictQuery, err := insert.conflictQuery(dialect)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query = query + conflictQuery
+	if len(insert.returning) > 0 {
+		if !dialect.Supports(FeatureReturning) {
+			return "", nil, &SyntaxError{syntax:insert.syntax(), err:errors.New("returning is not supported by " + dialect.Name())}
+		}
+		query = query + " RETURNING " + strings.Join(quoteIdentifiers(dialect, insert.returning), ",")
 	}
-	query = "INSERT INTO " + insert.table.Table() + " SET " + strings.TrimRight(query, ",")
+
+	return query,args,nil
+}
+
+// conflictQuery renders the ON DUPLICATE KEY UPDATE / ON CONFLICT clause
+func (insert *zInsert) conflictQuery(dialect Dialect) (query string, err error) {
+	if insert.keepExisting {
+		return insert.keepExistingQuery(dialect)
+	}
+
+	if len(insert.updates) == 0 {
+		if insert.ignore && !insert.replace && dialect.Supports(FeatureOnConflict) {
+			return " ON CONFLICT DO NOTHING", nil
+		}
+		return "", nil
+	}
+
+	var assigns = make([]string, 0, len(insert.updates))
+	switch {
+	case dialect.Supports(FeatureOnDuplicateKey):
+		for _,column := range quoteIdentifiers(dialect, insert.updates) {
+			assigns = append(assigns, column + "=VALUES(" + column + ")")
+		}
+		return " ON DUPLICATE KEY UPDATE " + strings.Join(assigns, ","), nil
+	case dialect.Supports(FeatureOnConflict):
+		var conflict = insert.conflict
+		if len(conflict) == 0 {
+			conflict = primaryKeys(insert.table)
+		}
+		if len(conflict) == 0 {
+			return "", &SyntaxError{syntax:insert.syntax(), err:errors.New("no conflict target")}
+		}
+
+		for _,column := range quoteIdentifiers(dialect, insert.updates) {
+			assigns = append(assigns, column + "=EXCLUDED." + column)
+		}
+		return " ON CONFLICT (" + strings.Join(quoteIdentifiers(dialect, conflict), ",") + ") DO UPDATE SET " + strings.Join(assigns, ","), nil
+	}
+
+	return "", &SyntaxError{syntax:insert.syntax(), err:errors.New("upsert is not supported by " + dialect.Name())}
+}
+
+// keepExistingQuery keeps the existing row without the errors hidden by
+// INSERT IGNORE, by a no-op update on mysql and DO NOTHING on conflict
+func (insert *zInsert) keepExistingQuery(dialect Dialect) (query string, err error) {
+	var conflict = insert.conflict
+	if len(conflict) == 0 {
+		conflict = primaryKeys(insert.table)
+	}
+
+	switch {
+	case dialect.Supports(FeatureOnDuplicateKey):
+		var column = assignColumns(insert.assigns)[0]
+		if len(conflict) > 0 {
+			column = conflict[0]
+		}
+		column = QuoteIdentifier(dialect, column)
+		return " ON DUPLICATE KEY UPDATE " + column + "=" + column, nil
+	case dialect.Supports(FeatureOnConflict):
+		if len(conflict) == 0 {
+			return " ON CONFLICT DO NOTHING", nil
+		}
+		return " ON CONFLICT (" + strings.Join(quoteIdentifiers(dialect, conflict), ",") + ") DO NOTHING", nil
+	}
+
+	return "", &SyntaxError{syntax:insert.syntax(), err:errors.New("upsert is not supported by " + dialect.Name())}
+}
+
+// assignColumns gives the sorted columns of the assign list
+// so that the rendered sql is stable
+func assignColumns(assigns AssignList) []string {
+	var columns = make([]string, 0, len(assigns))
+	for column := range assigns {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	return columns
+}
+
+// multiple rows insert syntax
+type zMInsert struct {
+	table 			ZTable
+	rows 			[]AssignList
+	dialect 		Dialect
+}
+
+func (insert *zMInsert) syntax() string {
+	return "MINSERT"
+}
+
+// query renders INSERT INTO t (cols) VALUES (...),(...), the columns
+// are taken from the first row and every row must assign the same columns
+func (insert *zMInsert) query() (query string, args []interface{}, err error) {
+	if insert.table == nil || insert.table.Table()=="" {
+		return "",nil, &SyntaxError{syntax:insert.syntax(), err:errors.New("no table defined")}
+	}
+
+	if insert.rows == nil || len(insert.rows) == 0 || len(insert.rows[0]) == 0 {
+		return "", nil, &SyntaxError{syntax:insert.syntax(), err:errors.New("no assignment")}
+	}
+
+	switch insert.table.(type) {
+	case *ZJoinTable:
+		return "", nil, &SyntaxError{syntax:insert.syntax(), err:errors.New("join table is not supported")}
+	}
+
+	var columns = assignColumns(insert.rows[0])
+	var values = "(" + strings.TrimRight(strings.Repeat("?,", len(columns)), ",") + ")"
+	var rows = make([]string, 0, len(insert.rows))
+	args = make([]interface{}, 0, len(columns)*len(insert.rows))
+	for i,row := range insert.rows {
+		if len(row) != len(columns) {
+			return "", nil, &SyntaxError{syntax:insert.syntax(), err:errors.Errorf("row %d assigns different columns", i)}
+		}
+
+		for _,column := range columns {
+			value, ok := row[column]
+			if !ok {
+				return "", nil, &SyntaxError{syntax:insert.syntax(), err:errors.Errorf("row %d has no column %s", i, column)}
+			}
+			args = append(args, value)
+		}
+		rows = append(rows, values)
+	}
+
+	var dialect = useDialect(insert.dialect)
+	query = "INSERT INTO " + tableName(dialect, insert.table) + " (" + strings.Join(quoteIdentifiers(dialect, columns), ",") + ") VALUES " + strings.Join(rows, ",")
 
 	return query,args,nil
 }
@@ -155,6 +363,7 @@ type zUpdate struct {
 	where 			*zWhere
 	orderBy 		*zOrderBy
 	limit 			*zLimit
+	dialect 		Dialect
 }
 
 func (update *zUpdate) syntax() string {
@@ -170,19 +379,24 @@ func (update *zUpdate) query() (query string, args []interface{}, err error) {
 		return "", nil, &SyntaxError{syntax:update.syntax(), err:errors.New("no assigns")}
 	}
 
+	var dialect = useDialect(update.dialect)
 	query = ""
 	if args = tableArgs(update.table); args == nil {
 		args = make([]interface{},0)
 	}
 	for column,value := range update.assigns {
-		query = query + column + "=?,"
+		query = query + QuoteIdentifier(dialect, column) + "=?,"
 		args = append(args, value)
 	}
 
-	query = "UPDATE " + update.table.Table() + " SET " + strings.TrimRight(query, ",")
+	query = "UPDATE " + tableName(dialect, update.table) + " SET " + strings.TrimRight(query, ",")
 
 	if update.where != nil {
-		wquery,wargs := update.where.build()
+		if werr := update.where.error(); werr != nil {
+			return "", nil, &SyntaxError{syntax:update.syntax(), err:werr}
+		}
+
+		wquery,wargs := update.where.build(dialect)
 		if wquery != "" {
 			query = query + " WHERE " + wquery
 			if wargs != nil {
@@ -192,9 +406,17 @@ func (update *zUpdate) query() (query string, args []interface{}, err error) {
 	}
 
 	if update.orderBy != nil {
-		oquery,oargs := update.orderBy.build()
+		if oerr := update.orderBy.error(); oerr != nil {
+			return "", nil, &SyntaxError{syntax:update.syntax(), err:oerr}
+		}
+
+		oquery,oargs := update.orderBy.build(dialect)
 
 		if oquery != "" {
+			if !dialect.Supports(FeatureUpdateLimit) {
+				return "", nil, &SyntaxError{syntax:update.syntax(), err:errors.New("order by is not supported by " + dialect.Name())}
+			}
+
 			query = query + " ORDER BY " + oquery
 			if oargs != nil {
 				args = append(args, oargs...)
@@ -203,7 +425,11 @@ func (update *zUpdate) query() (query string, args []interface{}, err error) {
 	}
 
 	if update.limit != nil {
-		lquery,largs := update.limit.build2()
+		if !dialect.Supports(FeatureUpdateLimit) {
+			return "", nil, &SyntaxError{syntax:update.syntax(), err:errors.New("limit is not supported by " + dialect.Name())}
+		}
+
+		lquery,largs := dialect.Limit(update.limit.rowCount, update.limit.iOffset, false)
 		if lquery != "" {
 			query = query + " LIMIT " + lquery
 			if largs != nil {
@@ -222,6 +448,16 @@ type zSelect struct {
 	groupby 	*zGroupBy
 	orderby 	*zOrderBy
 	limit 		*zLimit
+	dialect 	Dialect
+	// select expressions with args, rendered after the columns
+	exprs 		[]zColumnExpr
+	distinct 	bool
+	lock 		*zLock
+	// the query runs in a transaction, required by the lock
+	transaction bool
+	// the query is the body of a common table expression,
+	// the WITH clause is left to the main query
+	inCTE 		bool
 }
 
 func (sel *zSelect) syntax() string {
@@ -233,16 +469,40 @@ func (sel *zSelect) query(column ...string) (query string, args []interface{}, e
 		return "", nil, &SyntaxError{syntax:sel.syntax(), err:errors.New("no table defined")}
 	}
 
-	if column==nil || len(column)==0 {
+	var dialect = useDialect(sel.dialect)
+	var columns = append(make([]string, 0, len(column)+len(sel.exprs)), quoteColumns(dialect, column)...)
+	args = make([]interface{}, 0)
+	for _,expr := range sel.exprs {
+		if expr.err != nil {
+			return "", nil, &SyntaxError{syntax:sel.syntax(), err:expr.err}
+		}
+		columns = append(columns, expr.quoted(dialect))
+		args = append(args, expr.args...)
+	}
+
+	if len(columns)==0 {
 		return "", nil, &SyntaxError{syntax:sel.syntax(), err:errors.New("no column selected")}
 	}
 
-	query = "SELECT " + strings.Join(column, ",") + " FROM " + sel.table.Table()
-	if args = tableArgs(sel.table); args == nil {
-		args = make([]interface{}, 0)
+	query = "SELECT " + strings.Join(columns, ",") + " FROM " + tableName(dialect, sel.table)
+	if sel.distinct {
+		query = "SELECT DISTINCT " + strings.Join(columns, ",") + " FROM " + tableName(dialect, sel.table)
+	}
+	if !sel.inCTE {
+		if wquery,wargs := withClause(dialect, tableCTEs(sel.table)); wquery != "" {
+			query = wquery + " " + query
+			args = append(wargs, args...)
+		}
+	}
+	if targs := tableArgs(sel.table); targs != nil {
+		args = append(args, targs...)
 	}
 	if sel.where != nil {
-		wquery,wargs := sel.where.build()
+		if werr := sel.where.error(); werr != nil {
+			return "", nil, &SyntaxError{syntax:sel.syntax(), err:werr}
+		}
+
+		wquery,wargs := sel.where.build(dialect)
 		if wquery != "" {
 			query = query + " WHERE " + wquery
 			args = append(args, wargs...)
@@ -250,7 +510,11 @@ func (sel *zSelect) query(column ...string) (query string, args []interface{}, e
 	}
 
 	if sel.groupby != nil {
-		gquery,gargs := sel.groupby.build()
+		if gerr := sel.groupby.error(); gerr != nil {
+			return "", nil, &SyntaxError{syntax:sel.syntax(), err:gerr}
+		}
+
+		gquery,gargs := sel.groupby.build(dialect)
 		if gquery != "" {
 			query = query + " GROUP BY " + gquery
 			if gargs != nil {
@@ -260,7 +524,11 @@ func (sel *zSelect) query(column ...string) (query string, args []interface{}, e
 	}
 
 	if sel.orderby != nil {
-		oquery,oargs := sel.orderby.build()
+		if oerr := sel.orderby.error(); oerr != nil {
+			return "", nil, &SyntaxError{syntax:sel.syntax(), err:oerr}
+		}
+
+		oquery,oargs := sel.orderby.build(dialect)
 		if oquery != "" {
 			query = query + " ORDER BY " + oquery
 			if oargs != nil {
@@ -270,7 +538,7 @@ func (sel *zSelect) query(column ...string) (query string, args []interface{}, e
 	}
 
 	if sel.limit != nil {
-		lquery,largs := sel.limit.build()
+		lquery,largs := dialect.Limit(sel.limit.rowCount, sel.limit.iOffset, true)
 		if lquery != "" {
 			query = query + " LIMIT " + lquery
 			if largs != nil {
@@ -279,6 +547,22 @@ func (sel *zSelect) query(column ...string) (query string, args []interface{}, e
 		}
 	}
 
+	if sel.lock != nil {
+		lquery,lerr := sel.lock.build()
+		if lerr != nil {
+			return query, args, &SyntaxError{syntax:sel.syntax(), err:lerr}
+		}
+		if lquery != "" {
+			if !dialect.Supports(FeatureRowLock) {
+				return query, args, &SyntaxError{syntax:sel.syntax(), err:errors.New("row locking is not supported by " + dialect.Name())}
+			}
+			if !sel.transaction {
+				return query, args, &SyntaxError{syntax:sel.syntax(), err:errors.New("row locking needs a transaction")}
+			}
+			query = query + " " + lquery
+		}
+	}
+
 	return query, args, nil
 }
 
@@ -25,6 +25,23 @@ func TestDeleteSyntax(t *testing.T)  {
 	fmt.Println(query, args)
 }
 
+func TestMDeleteSyntax(t *testing.T) {
+	var joinTable = &ZJoinTable{TableReference: new(zTestTable1), Alias: "t1"}
+	joinTable.Join(new(zTestTable2), "t2", JoinOn(WhereRaw("t1.id = t2.id AND t2.c1 = ?", "a")))
+
+	var syntax = new(zMDelete)
+	syntax.table = joinTable
+	syntax.where = WhereColumn("t1.c2", "=", "b")
+
+	query,args,err := syntax.query("t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(args) != 2 || args[0] != "a" || args[1] != "b" {
+		t.Error("the join and where args should be kept: ", query, args)
+	}
+}
+
 func TestInsertSyntax(t *testing.T) {
 	var syntax = new(zInsert)
 	syntax.table = new(zTestTable1)
@@ -59,4 +76,68 @@ func TestSelectSyntax(t *testing.T)  {
 	syntax.limit = qb.limit
 
 	fmt.Println(syntax.query("c1, c2, SUM(c4)"))
-}
\ No newline at end of file
+}
+
+func TestMInsertSyntax(t *testing.T) {
+	var syntax = new(zMInsert)
+	syntax.table = new(zTestTable1)
+	syntax.rows = []AssignList{{"c1":"a", "c2":1}, {"c2":2, "c1":"b"}}
+
+	query,args,err := syntax.query()
+	if err != nil {
+		t.Error(err)
+	}
+	fmt.Println(query, args)
+
+	syntax.rows = append(syntax.rows, AssignList{"c1":"c", "c3":3})
+	if _,_,err := syntax.query(); err == nil {
+		t.Error("rows with different columns should fail")
+	}
+}
+
+func TestUpsertSyntax(t *testing.T) {
+	var expected = map[string][]string{
+		"mysql": {
+			"INSERT INTO `test1` SET `c1`=?,`c2`=?,`id`=? ON DUPLICATE KEY UPDATE `c1`=VALUES(`c1`),`c2`=VALUES(`c2`)",
+			"INSERT IGNORE INTO `test1` SET `c1`=?,`c2`=?,`id`=?",
+			"REPLACE INTO `test1` SET `c1`=?,`c2`=?,`id`=?",
+			"INSERT INTO `test1` SET `c1`=?,`c2`=?,`id`=? ON DUPLICATE KEY UPDATE `id`=`id`",
+			"INSERT INTO `test1` SET `c1`=?,`c2`=?,`id`=? ON DUPLICATE KEY UPDATE `c2`=VALUES(`c2`)",
+		},
+		"postgres": {
+			`INSERT INTO "test1" ("c1","c2","id") VALUES (?,?,?) ON CONFLICT ("id") DO UPDATE SET "c1"=EXCLUDED."c1","c2"=EXCLUDED."c2"`,
+			`INSERT INTO "test1" ("c1","c2","id") VALUES (?,?,?) ON CONFLICT DO NOTHING`,
+			"",
+			`INSERT INTO "test1" ("c1","c2","id") VALUES (?,?,?) ON CONFLICT ("id") DO NOTHING`,
+			`INSERT INTO "test1" ("c1","c2","id") VALUES (?,?,?) ON CONFLICT ("c1") DO UPDATE SET "c2"=EXCLUDED."c2"`,
+		},
+		"sqlite": {
+			`INSERT INTO "test1" ("c1","c2","id") VALUES (?,?,?) ON CONFLICT ("id") DO UPDATE SET "c1"=EXCLUDED."c1","c2"=EXCLUDED."c2"`,
+			`INSERT INTO "test1" ("c1","c2","id") VALUES (?,?,?) ON CONFLICT DO NOTHING`,
+			`REPLACE INTO "test1" ("c1","c2","id") VALUES (?,?,?)`,
+			`INSERT INTO "test1" ("c1","c2","id") VALUES (?,?,?) ON CONFLICT ("id") DO NOTHING`,
+			`INSERT INTO "test1" ("c1","c2","id") VALUES (?,?,?) ON CONFLICT ("c1") DO UPDATE SET "c2"=EXCLUDED."c2"`,
+		},
+	}
+
+	for _, dialect := range []Dialect{new(MySQLDialect), new(PostgresDialect), new(SQLiteDialect)} {
+		var syntaxes = []*zInsert{
+			{updates: []string{"c1", "c2"}},
+			{ignore: true},
+			{replace: true},
+			{keepExisting: true},
+			{updates: []string{"c2"}, conflict: []string{"c1"}},
+		}
+
+		for i, syntax := range syntaxes {
+			syntax.table = new(zTestTable1)
+			syntax.dialect = dialect
+			syntax.assigns = AssignList{"id": 1, "c1": "hhhh", "c2": 10}
+
+			query, _, err := syntax.query()
+			if want := expected[dialect.Name()][i]; query != want || (want == "") != (err != nil) {
+				t.Errorf("%s: unexpected upsert query %q, %v", dialect.Name(), query, err)
+			}
+		}
+	}
+}
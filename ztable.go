@@ -3,7 +3,6 @@ package zorm
 import (
 	"database/sql"
 	"reflect"
-	"time"
 	"strings"
 	"github.com/pkg/errors"
 	_ "github.com/go-sql-driver/mysql"
@@ -25,35 +24,177 @@ type ZTable interface {
 	SoftDelete() SoftDelete
 }
 
+// ZCompositeKeyTable is a table with a primary key of several columns,
+// the key values are given in the order of PrimaryKeys
+type ZCompositeKeyTable interface {
+	ZTable
+
+	// primary key columns
+	PrimaryKeys() []string
+}
+
+// primaryKeys gives the primary key columns of the table
+func primaryKeys(table ZTable) []string {
+	if composite, ok := table.(ZCompositeKeyTable); ok {
+		if keys := composite.PrimaryKeys(); len(keys) > 0 {
+			return keys
+		}
+	}
+
+	if table.PrimaryKey() == "" {
+		return nil
+	}
+
+	return []string{table.PrimaryKey()}
+}
+
+func inStrings(list []string, s string) bool {
+	for _,v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// keysWhere gives the condition matching the primary keys in ids, a slice
+// of keys, each key of a composite primary key is a slice of its values
+func keysWhere(columns []string, ids interface{}) (*zWhere, error) {
+	var r = reflect.ValueOf(ids)
+	if r.Kind() != reflect.Slice && r.Kind() != reflect.Array {
+		return nil, errors.Errorf("keys should be a slice, not %T", ids)
+	}
+
+	var keys = make([][]interface{}, 0, r.Len())
+	for i := 0; i < r.Len(); i++ {
+		key, err := keyValues(columns, r.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if len(columns) == 1 {
+		var values = make([]interface{}, 0, len(keys))
+		for _,key := range keys {
+			values = append(values, key[0])
+		}
+		return new(zWhere).In(columns[0], values...), nil
+	}
+
+	return new(zWhere).TupleIn(columns, keys...), nil
+}
+
+// keyWhere gives the condition matching the primary key id
+func keyWhere(columns []string, id interface{}) (*zWhere, error) {
+	values, err := keyValues(columns, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var where = new(zWhere)
+	for i,column := range columns {
+		where.Where(column, "=", values[i])
+	}
+
+	return where, nil
+}
+
+// keyValues gives the values of one key, id is the value of a single
+// column key, or a slice of the values of a composite key
+func keyValues(columns []string, id interface{}) ([]interface{}, error) {
+	if len(columns) == 0 {
+		return nil, errors.New("no primary key defined")
+	}
+
+	if len(columns) == 1 {
+		return []interface{}{id}, nil
+	}
+
+	var r = reflect.ValueOf(id)
+	if r.Kind() != reflect.Slice && r.Kind() != reflect.Array {
+		return nil, errors.Errorf("composite key should be a slice, not %T", id)
+	}
+	if r.Len() != len(columns) {
+		return nil, errors.Errorf("composite key has %d values for %d columns", r.Len(), len(columns))
+	}
+
+	var values = make([]interface{}, 0, len(columns))
+	for i := 0; i < r.Len(); i++ {
+		values = append(values, r.Index(i).Interface())
+	}
+
+	return values, nil
+}
+
 type ZJoinTable struct {
 	TableReference 		ZTable
 	Alias 				string
-	joinQuery 			string
-	joinArgs 			[]interface{}
+	// the joins, rendered by the dialect of the query
+	joins 				[]zJoin
+	// the joined tables, searched for common table expressions
+	joinTables 			[]ZTable
 }
 
-func (joinTable *ZJoinTable) Table() (name string) {
-	if joinTable.TableReference != nil {
-		var refTable = joinTable.TableReference.Table()
-		if joinTable.Alias != "" {
-			refTable = refTable + " AS " + joinTable.Alias
-		}
+// zJoin is a table joined to the table reference
+type zJoin struct {
+	syntax 		zJoinSyntax
+	table 		ZTable
+	alias 		string
+	condition 	zJoinCondition
+}
 
-		return refTable + " " + joinTable.joinQuery
-	}
-	return ""
+func (joinTable *ZJoinTable) Table() (name string) {
+	name,_ = joinTable.build(nil)
+	return name
 }
 
 func (joinTable *ZJoinTable) tableArgs() (args []interface{}) {
-	if args = tableArgs(joinTable.TableReference); args != nil {
-		if joinTable.joinArgs != nil {
-			args = append(args, joinTable.joinArgs...)
+	_,args = joinTable.build(nil)
+	return args
+}
+
+// build renders the joined tables with the identifiers quoted by the dialect
+func (joinTable *ZJoinTable) build(dialect Dialect) (query string, args []interface{}) {
+	if joinTable.TableReference == nil {
+		return "", nil
+	}
+
+	query = tableName(dialect, joinTable.TableReference)
+	if joinTable.Alias != "" {
+		query = query + " AS " + useDialect(dialect).Quote(joinTable.Alias)
+	}
+	query = query + " "
+	args = tableArgs(joinTable.TableReference)
+
+	for _,join := range joinTable.joins {
+		name := tableName(dialect, join.table)
+		if _, ok := join.table.(*ZJoinTable); ok {
+			name = "(" + name + ")"
+		}
+		query = query + " " + join.syntax.String() + " " + name
+
+		if targs := tableArgs(join.table); targs != nil {
+			args = append(args, targs...)
+		}
+
+		if join.alias != "" {
+			query = query + " AS " + useDialect(dialect).Quote(join.alias)
 		}
 
-		return args
+		if join.condition != nil {
+			cquery,cargs := join.condition.joinCondition(dialect)
+			if cquery != "" {
+				query = query + " " + cquery
+
+				if cargs != nil {
+					args = append(args, cargs...)
+				}
+			}
+		}
 	}
 
-	return joinTable.joinArgs
+	return query, args
 }
 
 func (joinTable *ZJoinTable) PrimaryKey() string {
@@ -91,38 +232,35 @@ func (joinTable *ZJoinTable) join(table ZTable, joinSyntax zJoinSyntax, alias st
 
 	switch joinSyntax {
 	case zInnerJoin,zLeftJoin,zRightJoin:
-		name := table.Table()
-		joinTable.joinQuery = joinTable.joinQuery + " " + joinSyntax.String() + " (" + name + ")"
 	default:
 		return joinTable
 	}
 
-	if args := tableArgs(table); args != nil {
-		joinTable.joinArgs = append(joinTable.joinArgs, args...)
-	}
-
-	if alias != "" {
-		joinTable.joinQuery = joinTable.joinQuery + " AS " + alias
-	}
+	joinTable.joins = append(joinTable.joins, zJoin{syntax: joinSyntax, table: table, alias: alias, condition: joinCondition})
+	joinTable.joinTables = append(joinTable.joinTables, table)
 
-	if joinCondition != nil {
-		query,args := joinCondition.joinCondition()
-		if query != "" {
-			joinTable.joinQuery = joinTable.joinQuery + " " + query
+	return joinTable
+}
 
-			if args != nil {
-				joinTable.joinArgs = append(joinTable.joinArgs, args...)
-			}
-		}
+// tableName renders the table of a query, a table name is quoted by the dialect
+func tableName(dialect Dialect, table ZTable) string {
+	switch t := table.(type) {
+	case *ZJoinTable:
+		name,_ := t.build(dialect)
+		return name
+	case *zSubQuery:
+		return t.tableQuery(dialect)
 	}
 
-	return joinTable
+	return quoteColumn(dialect, table.Table())
 }
 
 func tableArgs(table ZTable) (args []interface{}) {
 	switch table.(type) {
 	case *ZJoinTable:
 		return table.(*ZJoinTable).tableArgs()
+	case *zSubQuery:
+		return table.(*zSubQuery).args
 	}
 
 	return nil
@@ -145,14 +283,19 @@ type SoftDelete interface {
 
 type ZColumnList ZMap
 
+// Bind appends the zcolumn tagged fields of the struct
+// with the zero value of the field type as the template
 func (column ZColumnList) Bind(obj interface{}) (*ZColumnList) {
-	var r = reflect.ValueOf(obj).Elem()
-	var numFields = r.NumField()
+	var t = reflect.TypeOf(obj)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return &column
+	}
 
-	for i := 0; i < numFields; i++ {
-		if fk,ok := r.Type().Field(i).Tag.Lookup("zcolumn"); ok {
-			column.Append(fk, reflect.Zero(r.Field(i).Type()).Interface())
-		}
+	for _, field := range structFields(t) {
+		column.Append(field.column, reflect.Zero(field.typ).Interface())
 	}
 	return &column
 }
@@ -237,22 +380,29 @@ type zRow struct {
 	filledMap 		ZMap
 }
 
+// Bind sets the zcolumn tagged fields of the struct with the row values,
+// converting between compatible types
 func (row *zRow) Bind(obj interface{}) (error) {
-	r := reflect.ValueOf(obj).Elem()
-
-	switch r.Kind() {
-	case reflect.Struct:
-		numFields := r.NumField()
-		for i := 0; i < numFields; i++ {
-			field := r.Type().Field(i)
-			if column,ok := field.Tag.Lookup("zcolumn"); ok {
-				if v, ok := row.filledMap[column]; ok {
-					r.Field(i).Set(reflect.ValueOf(v))
-				}
-			}
+	r := reflect.ValueOf(obj)
+	if r.Kind() != reflect.Ptr || r.IsNil() || r.Elem().Kind() != reflect.Struct {
+		return errors.New("you should bind a pointer to struct")
+	}
+
+	r = r.Elem()
+	for _, field := range structFields(r.Type()) {
+		v, ok := row.Get(field.column)
+		if !ok {
+			continue
+		}
+
+		dest, ok := fieldByIndex(r, field.index, true)
+		if !ok {
+			continue
+		}
+
+		if err := assignValue(dest, v); err != nil {
+			return errors.Wrapf(err, "bind column %s", field.column)
 		}
-	default:
-		return errors.New("you should bind an struct")
 	}
 
 	return nil
@@ -285,8 +435,6 @@ func (row *zRow) Get(key string) (interface{}, bool) {
 
 		return v,ok
 	}
-
-	return nil,false
 }
 
 func (row *zRow) fill(sqlRow zScanner) (error) {
@@ -306,30 +454,47 @@ func (row *zRow) fill(sqlRow zScanner) (error) {
 		return err
 	}
 
-	row.cover(row.value, sqlRowValue)
+	row.filledMap = nil
+	row.fillValues(sqlRowValue)
+	return nil
+}
 
-	row.filledMap = make(ZMap)
+// fillValues fills the scanned values into filledMap,
+// filledMap is cleared and reused if not nil
+func (row *zRow) fillValues(sqlRowValue []interface{}) {
+	// row.value is the template shared by all rows of zRows,
+	// the scanned values are covered into a copy of it
+	var value = make([]interface{}, len(row.value))
+	copy(value, row.value)
+	row.cover(value, sqlRowValue)
+
+	if row.filledMap == nil {
+		row.filledMap = make(ZMap)
+	} else {
+		for k := range row.filledMap {
+			delete(row.filledMap, k)
+		}
+	}
 	for idx,column := range row.columns {
 		if idxAs := strings.Index(strings.ToUpper(column), " AS "); idxAs > 0 {
 			alias := strings.Trim(column[idxAs+3:], " ")
 			if alias != "" {
-				row.filledMap[alias] = row.value[idx]
+				row.filledMap[alias] = value[idx]
 			}
 		} else if idxDot := strings.Index(column, "."); idxDot>0 {
 			tableAlias := strings.Trim(column[:idxDot], " ")
 			columnAlias := strings.Trim(column[idxDot+1:], " ")
 			if tableAlias!="" && columnAlias!="" {
 				if _, ok := row.filledMap[tableAlias]; ok {
-					row.filledMap[tableAlias].(ZMap)[columnAlias] = row.value[idx]
+					row.filledMap[tableAlias].(ZMap)[columnAlias] = value[idx]
 				} else {
-					row.filledMap[tableAlias] = ZMap{columnAlias: row.value[idx]}
+					row.filledMap[tableAlias] = ZMap{columnAlias: value[idx]}
 				}
 			}
 		} else {
-			row.filledMap[strings.Trim(column, " ")] = row.value[idx]
+			row.filledMap[strings.Trim(column, " ")] = value[idx]
 		}
 	}
-	return nil
 }
 
 func (row *zRow) cover(dest, sqlRowValue []interface{}) {
@@ -338,36 +503,27 @@ func (row *zRow) cover(dest, sqlRowValue []interface{}) {
 	}
 
 	for i := 0; i < len(sqlRowValue); i++ {
-		v := reflect.New(reflect.TypeOf(dest[i]))
-		switch sqlRowValue[i].(type) {
-		case int64:
-			v.Elem().SetInt(sqlRowValue[i].(int64))
-			dest[i] = v.Elem().Interface()
-		case float64:
-			v.Elem().SetFloat(sqlRowValue[i].(float64))
-			dest[i] = v.Elem().Interface()
-		case bool:
-			v.Elem().SetBool(sqlRowValue[i].(bool))
-			dest[i] = v.Elem().Interface()
-		case []byte:
-			switch dest[i].(type) {
-			case string:
-				v.Elem().SetString(string(sqlRowValue[i].([]byte)))
-			case []byte:
-				v.Elem().SetBytes(sqlRowValue[i].([]byte))
-			}
-			dest[i] = v.Elem().Interface()
-		case string:
-			v.Elem().SetString(sqlRowValue[i].(string))
-			dest[i] = v.Elem().Interface()
-		case time.Time:
-			switch dest[i].(type) {
-			case string:
-				v.Elem().SetString(sqlRowValue[i].(time.Time).Format("2006-01-02 15:04:05"))
-				dest[i] = v.Elem().Interface()
-			case time.Time:
-				dest[i] = sqlRowValue[i].(time.Time)
-			}
+		dest[i] = coverValue(dest[i], sqlRowValue[i])
+	}
+}
+
+// coverValue converts the scanned value to the type of the template value.
+// NULL gives nil, or a null value if the template is a pointer or
+// a sql.Scanner such as sql.NullString
+func coverValue(template, value interface{}) interface{} {
+	if template == nil {
+		return value
+	}
+
+	var v = reflect.New(reflect.TypeOf(template)).Elem()
+	if value == nil {
+		if _, ok := v.Addr().Interface().(sql.Scanner); !ok && v.Kind() != reflect.Ptr {
+			return nil
 		}
 	}
-}
\ No newline at end of file
+
+	if err := assignValue(v, value); err != nil {
+		return value
+	}
+	return v.Interface()
+}
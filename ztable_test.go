@@ -1,6 +1,7 @@
 package zorm
 
 import (
+	"database/sql"
 	"time"
 	"testing"
 	"fmt"
@@ -56,6 +57,33 @@ func (test *zTestTable2) SoftDelete() SoftDelete {
 	return &zTestSoftDelete{}
 }
 
+type zTestTable3 struct {
+}
+
+func (test *zTestTable3) Table() string {
+	return "test3"
+}
+
+func (test *zTestTable3) PrimaryKey() string {
+	return ""
+}
+
+func (test *zTestTable3) PrimaryKeys() []string {
+	return []string{"tenant_id", "id"}
+}
+
+func (test *zTestTable3) Columns() *ZColumnList {
+	return &ZColumnList{
+		"tenant_id": int64(0),
+		"id": "uuid",
+		"c1": "string column",
+	}
+}
+
+func (test *zTestTable3) SoftDelete() SoftDelete {
+	return nil
+}
+
 type zTestSoftDelete struct {
 }
 
@@ -90,10 +118,81 @@ func TestZJoinTable_Columns(t *testing.T) {
 	fmt.Println(joinTable.tableArgs())
 }
 
+func TestZJoinTable_Nested(t *testing.T) {
+	var inner = &ZJoinTable{TableReference: new(zTestTable1), Alias: "t1"}
+	inner.Join(new(zTestTable2), "t2", JoinOn(WhereRaw("t1.id = t2.id")))
+
+	var joinTable = &ZJoinTable{TableReference: new(zTestTable1), Alias: "t3"}
+	joinTable.LeftJoin(inner, "", JoinOn(WhereRaw("t3.id = t1.id")))
+
+	if joinTable.Table() != "`test1` AS `t3`  LEFT JOIN (`test1` AS `t1`  INNER JOIN `test2` AS `t2`  ON (t1.id = t2.id))  ON (t3.id = t1.id)" {
+		t.Error("only a joined table should be parenthesized: " + joinTable.Table())
+	}
+}
+
 func TestZColumnList_Bind(t *testing.T) {
 	var tst testColumnStruct
 
 	var column = ZColumnList{}.Bind(&tst)
 
 	fmt.Println(column)
-}
\ No newline at end of file
+}
+
+// zTestScanner scans the values into dest like *sql.Row does with interface{} dest
+type zTestScanner struct {
+	values 	[]interface{}
+}
+
+func (scanner *zTestScanner) Scan(dest ...interface{}) error {
+	for i := range dest {
+		*(dest[i].(*interface{})) = scanner.values[i]
+	}
+	return nil
+}
+
+func TestZRow_FillNull(t *testing.T) {
+	var row = ZColumnList{
+		"c1": "string column",
+		"c2": sql.NullString{},
+		"c3": (*int64)(nil),
+	}.makeRow()
+
+	var values = make([]interface{}, len(row.columns))
+	for i := range row.columns {
+		values[i] = nil
+	}
+	if err := row.fill(&zTestScanner{values: values}); err != nil {
+		t.Error(err)
+	}
+	fmt.Println(row.filledMap)
+
+	if v, _ := row.Get("c1"); v != nil {
+		t.Error("NULL should be filled as nil")
+	}
+	if v, _ := row.Get("c2"); v.(sql.NullString).Valid {
+		t.Error("NULL should be filled as invalid sql.NullString")
+	}
+	if v, _ := row.Get("c3"); v.(*int64) != nil {
+		t.Error("NULL should be filled as nil pointer")
+	}
+
+	for i, column := range row.columns {
+		values[i] = map[string]interface{}{"c1": []byte("a"), "c2": []byte("b"), "c3": int64(3)}[column]
+	}
+	if err := row.fill(&zTestScanner{values: values}); err != nil {
+		t.Error(err)
+	}
+	fmt.Println(row.filledMap)
+
+	var obj struct {
+		C1 		string 			`zcolumn:"c1"`
+		C2 		sql.NullString 	`zcolumn:"c2"`
+		C3 		*int64 			`zcolumn:"c3"`
+	}
+	if err := row.Bind(&obj); err != nil {
+		t.Error(err)
+	}
+	if obj.C1 != "a" || obj.C2.String != "b" || obj.C3 == nil || *obj.C3 != 3 {
+		t.Error("unexpected bind result")
+	}
+}
@@ -0,0 +1,188 @@
+package zorm
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// Model is a typed model on top of zModel, T is a struct
+// with zcolumn tags, the columns are taken from T
+type Model[T any] struct {
+	model 		*zModel
+}
+
+// NewModelOf gives a typed model of T with the db connection
+func NewModelOf[T any](connection *ZConnection, table ZTable, sqlLogger zSqlLogger) (*Model[T]) {
+	return &Model[T]{model: connection.NewModel(table, sqlLogger)}
+}
+
+// NewQuery starts a new query used by Get, First and Count
+func (m *Model[T]) NewQuery() (*zQueryBuilder) {
+	return m.model.NewQuery()
+}
+
+// Find gives the row with the primary key id, nil if not found,
+// id is a slice of the key values for a ZCompositeKeyTable
+func (m *Model[T]) Find(ctx context.Context, id interface{}) (*T, error) {
+	var obj = new(T)
+	found, err := m.model.FindIntoContext(ctx, id, obj)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+
+	return obj, nil
+}
+
+// First gives the first row of the current query, nil if not found
+func (m *Model[T]) First(ctx context.Context) (*T, error) {
+	var obj = new(T)
+	found, err := m.model.FirstIntoContext(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+
+	return obj, nil
+}
+
+// Get gives the rows of the current query
+func (m *Model[T]) Get(ctx context.Context) ([]T, error) {
+	var objs = make([]T, 0)
+	if err := m.model.GetIntoContext(ctx, &objs); err != nil {
+		return nil, err
+	}
+
+	return objs, nil
+}
+
+// Count gives the number of rows of the current query
+func (m *Model[T]) Count(ctx context.Context) (int64, error) {
+	total, err := m.model.CountContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// Insert inserts obj, the zero primary key fields are left to the
+// database and set to the inserted key afterwards
+func (m *Model[T]) Insert(ctx context.Context, obj *T) (error) {
+	var list = AssignList{}.Bind(obj)
+	var keys, ok = m.keyFields(obj)
+	var zeroKeys = make([]int, 0)
+	if ok {
+		for i, key := range keys {
+			if key.value.IsZero() {
+				list.Delete(key.column)
+				zeroKeys = append(zeroKeys, i)
+			}
+		}
+	}
+
+	if len(zeroKeys) == 0 {
+		if _, err := m.model.InsertContext(ctx, list); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	key, err := m.model.InsertKeyContext(ctx, list)
+	if err != nil {
+		return err
+	}
+	if key == nil {
+		return nil
+	}
+
+	var values = []interface{}{key}
+	if len(keys) > 1 {
+		values = key.([]interface{})
+	}
+	for _, i := range zeroKeys {
+		if values[i] == nil || reflect.ValueOf(values[i]).IsZero() {
+			continue
+		}
+		if serr := assignValue(keys[i].value, values[i]); serr != nil {
+			return errors.Wrapf(serr, "set primary key %s", keys[i].column)
+		}
+	}
+
+	return nil
+}
+
+// Update updates the row of obj by its primary key only,
+// the conditions of the current query are not applied
+func (m *Model[T]) Update(ctx context.Context, obj *T) (rowsAffected int64, err error) {
+	var keys, ok = m.keyFields(obj)
+	if !ok {
+		return 0, errors.New("no primary key value to update")
+	}
+
+	var list = AssignList{}.Bind(obj)
+	for _, key := range keys {
+		if key.value.IsZero() {
+			return 0, errors.New("no primary key value to update")
+		}
+		list.Assign(key.column, key.value.Interface())
+	}
+
+	var query = m.model.query
+	m.model.query = nil
+	defer func() { m.model.query = query }()
+
+	rowsAffected, merr := m.model.UpdateContext(ctx, list)
+	if merr != nil {
+		return rowsAffected, merr
+	}
+
+	return rowsAffected, nil
+}
+
+// zKeyField is a struct field mapped to a primary key column
+type zKeyField struct {
+	column 		string
+	value 		reflect.Value
+}
+
+// keyFields gives the fields of obj mapped to the primary key columns,
+// ok is false if a key column is not mapped
+func (m *Model[T]) keyFields(obj *T) (keys []zKeyField, ok bool) {
+	var columns = primaryKeys(m.model.table)
+	if len(columns) == 0 || obj == nil {
+		return nil, false
+	}
+
+	var r = reflect.ValueOf(obj).Elem()
+	if r.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	var fields = structFields(r.Type())
+	for _, column := range columns {
+		var found = false
+		for _, field := range fields {
+			if field.column == column {
+				value, ok := fieldByIndex(r, field.index, true)
+				if !ok {
+					return nil, false
+				}
+				keys = append(keys, zKeyField{column: column, value: value})
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+
+	return keys, true
+}
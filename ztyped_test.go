@@ -0,0 +1,41 @@
+package zorm
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+)
+
+func TestModel_Typed(t *testing.T) {
+	var connection = zTestConnection(t, "model_typed")
+	var model = NewModelOf[testIntoStruct](connection, new(zTestTable1), nil)
+	var ctx = context.Background()
+
+	var obj = testIntoStruct{Name: "a"}
+	if err := model.Insert(ctx, &obj); err != nil {
+		t.Error(err)
+	}
+	if obj.Id != 1 {
+		t.Error("primary key should be set after insert")
+	}
+
+	obj.Name = "b"
+	if _, err := model.Update(ctx, &obj); err != nil {
+		t.Error(err)
+	}
+
+	zTestResults["model_typed"] = [][]driver.Value{{int64(1), []byte("b"), nil}}
+	found, err := model.Find(ctx, 1)
+	if err != nil || found == nil || found.Name != "b" {
+		t.Error("unexpected find result")
+	}
+
+	model.NewQuery().Where("c1", "=", "b")
+	objs, err := model.Get(ctx)
+	if err != nil || len(objs) != 1 {
+		t.Error("unexpected get result")
+	}
+
+	fmt.Println(objs, zTestQueries("model_typed"))
+}
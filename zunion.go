@@ -0,0 +1,186 @@
+package zorm
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type zUnionPart struct {
+	query 		string
+	args 		[]interface{}
+	all 		bool
+}
+
+// zUnion combines the select queries of several models with
+// UNION / UNION ALL, the result columns are named by the first query
+type zUnion struct {
+	model 		*zModel
+	columns 	[]string
+	parts 		[]zUnionPart
+	orderBy 	*zOrderBy
+	limit 		*zLimit
+	err 		*zModelErr
+}
+
+// Union starts a union with the current query of the model selecting column,
+// the queries added later should select the same number of columns
+func (model *zModel) Union(column ...string) (*zUnion) {
+	var union = &zUnion{model: model, columns: column}
+	return union.add(model, column, false)
+}
+
+// Union adds the current query of the model with UNION
+func (union *zUnion) Union(model *zModel, column ...string) (*zUnion) {
+	return union.add(model, column, false)
+}
+
+// UnionAll adds the current query of the model with UNION ALL
+func (union *zUnion) UnionAll(model *zModel, column ...string) (*zUnion) {
+	return union.add(model, column, true)
+}
+
+func (union *zUnion) add(model *zModel, column []string, all bool) (*zUnion) {
+	if union.err != nil {
+		return union
+	}
+
+	if model == nil {
+		union.err = &zModelErr{err:errors.New("no model to union")}
+		return union
+	}
+
+	if err := model.checkStrict(column...); err != nil {
+		union.err = &zModelErr{err:err}
+		return union
+	}
+
+	var syntax = model.selectSyntax()
+	query,args,err := syntax.query(column...)
+	if err != nil {
+		union.err = &zModelErr{query:query, args:args, err:err}
+		return union
+	}
+
+	if len(union.parts) == 0 {
+		for _,expr := range syntax.exprs {
+			union.columns = append(union.columns, expr.column())
+		}
+	}
+
+	union.parts = append(union.parts, zUnionPart{query: query, args: args, all: all})
+	return union
+}
+
+// OrderBy orders the union result by a result column
+func (union *zUnion) OrderBy(column, sort string) (*zUnion) {
+	if union.orderBy == nil {
+		union.orderBy = new(zOrderBy)
+	}
+	union.orderBy.OrderBy(column, sort)
+	return union
+}
+
+func (union *zUnion) Limit(rowCount int64) (*zUnion) {
+	if union.limit == nil {
+		union.limit = new(zLimit)
+	}
+	union.limit.Limit(rowCount)
+	return union
+}
+
+func (union *zUnion) Paginate(offset,limit int64) (*zUnion) {
+	if union.limit == nil {
+		union.limit = new(zLimit)
+	}
+	union.limit.Limit(limit).Offset(offset)
+	return union
+}
+
+func (union *zUnion) query() (query string, args []interface{}, err error) {
+	if len(union.parts) < 2 {
+		return "", nil, &SyntaxError{syntax:"UNION", err:errors.New("union needs at least two queries")}
+	}
+
+	var dialect = useDialect(union.model.dialect)
+	var parts = make([]string, 0, len(union.parts))
+	args = make([]interface{}, 0)
+	for i,part := range union.parts {
+		var partQuery = "(" + part.query + ")"
+		if !dialect.Supports(FeatureCompoundParens) {
+			partQuery = "SELECT * FROM (" + part.query + ")"
+		}
+
+		switch {
+		case i == 0:
+		case part.all:
+			partQuery = "UNION ALL " + partQuery
+		default:
+			partQuery = "UNION " + partQuery
+		}
+
+		parts = append(parts, partQuery)
+		args = append(args, part.args...)
+	}
+	query = strings.Join(parts, " ")
+
+	if union.orderBy != nil {
+		if oerr := union.orderBy.error(); oerr != nil {
+			return "", nil, &SyntaxError{syntax:"UNION", err:oerr}
+		}
+		if oquery,_ := union.orderBy.build(dialect); oquery != "" {
+			query = query + " ORDER BY " + oquery
+		}
+	}
+
+	if union.limit != nil {
+		lquery,largs := dialect.Limit(union.limit.rowCount, union.limit.iOffset, true)
+		query = query + " LIMIT " + lquery
+		args = append(args, largs...)
+	}
+
+	return query, args, nil
+}
+
+// Get runs the union and gives the rows, the values are converted to
+// the type of the template in column by the result column name,
+// or left as they are scanned if there is no template
+func (union *zUnion) Get(column *ZColumnList) (*zRows, *zModelErr) {
+	return union.GetContext(context.Background(), column)
+}
+
+func (union *zUnion) GetContext(ctx context.Context, column *ZColumnList) (*zRows, *zModelErr) {
+	if union.err != nil {
+		return nil, union.err
+	}
+
+	query,args,err := union.query()
+	if err != nil {
+		return nil, &zModelErr{query:query, args:args, err:err}
+	}
+
+	var rows = new(zRows)
+	rows.columns = union.columns
+	rows.value = make([]interface{}, len(union.columns))
+	if column != nil {
+		for i,c := range union.columns {
+			name := c
+			if idxAs := strings.Index(strings.ToUpper(c), " AS "); idxAs > 0 {
+				name = strings.TrimSpace(c[idxAs+4:])
+			}
+			rows.value[i] = (*column)[name]
+		}
+	}
+
+	sqlRows,qerr := union.model.queryRows(ctx, query, args...)
+	if qerr != nil {
+		return nil, qerr
+	}
+
+	if err := rows.fill(sqlRows); err != nil {
+		return nil, &zModelErr{query:query, args:args, err:err}
+	}
+
+	return rows, nil
+}
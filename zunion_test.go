@@ -0,0 +1,37 @@
+package zorm
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"testing"
+)
+
+func TestZUnion(t *testing.T) {
+	var connection = zTestConnection(t, "model_union")
+	zTestResults["model_union"] = [][]driver.Value{{int64(1), []byte("a")}, {int64(2), []byte("b")}}
+
+	var m1 = connection.NewModel(new(zTestTable1), nil)
+	m1.NewQuery().Where("c2", ">", 1)
+	var m2 = connection.NewModel(new(zTestTable2), nil)
+	m2.NewQuery().Where("c2", "<", 1).OrderBy("id", "DESC").Limit(10)
+
+	rows, err := m1.Union("id", "c1 AS name").UnionAll(m2, "id", "c3").OrderBy("name", "").Limit(5).
+		Get(&ZColumnList{"name": ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queries := zTestQueries("model_union")
+	fmt.Println(queries, rows.Rows())
+	if queries[0] != "(SELECT `id`,`c1` AS `name` FROM `test1` WHERE (`c2` > ?)) UNION ALL " +
+		"(SELECT `id`,`c3` FROM `test2` WHERE (`delete_time` = ?) AND ((`c2` < ?)) ORDER BY `id` DESC LIMIT ? OFFSET ?) ORDER BY `name` ASC LIMIT ? OFFSET ?" {
+		t.Error("unexpected union query: " + queries[0])
+	}
+	if v, _ := rows.Rows()[1].Get("name"); v != "b" {
+		t.Error("unexpected union rows")
+	}
+
+	if _, err := m1.Union("id").Get(nil); err == nil {
+		t.Error("union of one query should fail")
+	}
+}